@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// gameSVG draws the final position of a game of p described by events as an
+// SVG image, with an arrow for each of the Rook's moves.
+func gameSVG(p problem, events []event) (string, error) {
+	rookSquare, bishopSquare := p.rook, p.bishop
+	arrows := make([]internal.Arrow, 0)
+	roll := 0
+	for _, e := range events {
+		switch e := e.(type) {
+		case diceRolled:
+			roll = e.Roll
+		case coinTossed:
+			// The toss comes before the move, so the Rook is still on the
+			// square it leaves.
+			from, err := internal.NewPosition(rookSquare)
+			if err != nil {
+				return "", err
+			}
+			step := directions[p.tails]
+			if e.Heads {
+				step = directions[p.heads]
+			}
+			arrows = append(arrows, internal.Arrow{From: *from, Files: step[0] * roll, Ranks: step[1] * roll})
+		case pieceMoved:
+			rookSquare = e.To
+		case pieceCaptured:
+			bishopSquare = ""
+		}
+	}
+	board := internal.NewBoard()
+	if err := board.PlacePiece(internal.NewRook(internal.BLACK), rookSquare); err != nil {
+		return "", err
+	}
+	if bishopSquare != "" {
+		if err := board.PlacePiece(internal.NewBishop(internal.WHITE), bishopSquare); err != nil {
+			return "", err
+		}
+	}
+	return board.RenderSVG(internal.SVGOptions{Arrows: arrows}), nil
+}
+
+// writeGameSVG writes gameSVG's diagram of a game of p to path.
+func writeGameSVG(path string, p problem, events []event) error {
+	svg, err := gameSVG(p, events)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(svg), 0644)
+}
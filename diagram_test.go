@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGameSVG(t *testing.T) {
+	// Tails 3 takes the Rook from h1 around the edge to c1, then Heads 2 takes
+	// the Bishop on c3.
+	events, err := replay(defaultProblem(), []bool{false, true}, []int{3, 2})
+	if err != nil {
+		t.Fatalf("replay returned err %v", err)
+	}
+	got, err := gameSVG(defaultProblem(), events)
+	if err != nil {
+		t.Fatalf("gameSVG returned err %v", err)
+	}
+	// The wrapped move takes two lines and the capture one.
+	if n := strings.Count(got, "<line"); n != 3 {
+		t.Errorf("gameSVG drew %v lines, wanted 3:\n%v", n, got)
+	}
+	if !strings.Contains(got, "♜") || strings.Contains(got, "♗") {
+		t.Errorf("gameSVG should draw the Rook but not the captured Bishop:\n%v", got)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// event is something that happened during a game.  Games emit a slice of
+// events, and renderers such as renderText turn them into output.
+type event interface {
+	// eventType names the kind of event, such as "CoinTossed".
+	eventType() string
+}
+
+// diceRolled records a roll of the dice deciding how far a piece moves.
+type diceRolled struct {
+	Turn int
+	Roll int
+}
+
+// coinTossed records a coin toss deciding which way a piece moves.
+type coinTossed struct {
+	Turn  int
+	Heads bool
+}
+
+// directionSpun records a spinner deciding which way a piece moves.
+type directionSpun struct {
+	Turn      int
+	Direction string
+}
+
+// pieceMoved records a piece moving, or staying put if From equals To.
+type pieceMoved struct {
+	Turn int
+
+	// Piece is the piece's color and name, such as "Black Rook".
+	Piece string
+
+	From string
+	To   string
+}
+
+// piecePassed records a piece giving up its turn because it has no legal
+// move, such as when every square it could reach leaves its King in check.
+type piecePassed struct {
+	Turn int
+
+	// Piece is the piece's color and name, such as "White Rook".
+	Piece string
+}
+
+// pieceCaptured records a piece being taken.
+type pieceCaptured struct {
+	Turn int
+
+	// Piece is the color and name of the piece taken, and By that of the
+	// piece taking it.
+	Piece string
+	By    string
+
+	Square string
+}
+
+// boardShown records a diagram of the board, drawn by internal.Board.Render.
+type boardShown struct {
+	Turn    int
+	Diagram string
+}
+
+// gameEnded records the end of the game.
+type gameEnded struct {
+	// Turn is the last turn played.
+	Turn int
+
+	// Winner is internal.EMPTY if nobody won.
+	Winner internal.Color
+
+	Reason string
+}
+
+func (diceRolled) eventType() string    { return "DiceRolled" }
+func (coinTossed) eventType() string    { return "CoinTossed" }
+func (directionSpun) eventType() string { return "DirectionSpun" }
+func (pieceMoved) eventType() string    { return "PieceMoved" }
+func (piecePassed) eventType() string   { return "PiecePassed" }
+func (pieceCaptured) eventType() string { return "PieceCaptured" }
+func (boardShown) eventType() string    { return "BoardShown" }
+func (gameEnded) eventType() string     { return "GameEnded" }
+
+// String returns the outcome as logged, such as "Rook escapes, Black wins".
+func (g gameEnded) String() string {
+	if g.Winner == internal.EMPTY {
+		return fmt.Sprintf("%v, nobody wins", g.Reason)
+	}
+	return fmt.Sprintf("%v, %v wins", g.Reason, g.Winner)
+}
+
+// pieceName returns piece's color and name, such as "Black Rook".
+func pieceName(piece internal.ChessPiece) string {
+	return fmt.Sprintf("%v %v", piece.GetColor(), piece.GetName())
+}
+
+// moveEvents describes piece moving from one square to another as a
+// pieceMoved event, preceded by a pieceCaptured event if captured is not nil.
+func moveEvents(turn int, piece internal.ChessPiece, from, to internal.Position, captured internal.ChessPiece) []event {
+	out := make([]event, 0, 2)
+	if captured != nil {
+		out = append(out, pieceCaptured{
+			Turn:   turn,
+			Piece:  pieceName(captured),
+			By:     pieceName(piece),
+			Square: to.String(),
+		})
+	}
+	return append(out, pieceMoved{
+		Turn:  turn,
+		Piece: pieceName(piece),
+		From:  from.String(),
+		To:    to.String(),
+	})
+}
+
+// lastGameEnded returns the gameEnded event closing events, or false if the
+// game did not finish.
+func lastGameEnded(events []event) (gameEnded, bool) {
+	if len(events) == 0 {
+		return gameEnded{}, false
+	}
+	ended, ok := events[len(events)-1].(gameEnded)
+	return ended, ok
+}
+
+// renderText turns events into the human readable log lines of the
+// original program, such as "Heads, rolled 5" and "Black Rook at e1".
+func renderText(events []event) []string {
+	out := make([]string, 0)
+	// A roll is held back to share a line with the direction chosen after it.
+	var roll *diceRolled
+	flushRoll := func() {
+		if roll != nil {
+			out = append(out, fmt.Sprintf("Rolled %v", roll.Roll))
+			roll = nil
+		}
+	}
+	choice := func(name string) {
+		if roll != nil {
+			out = append(out, fmt.Sprintf("%v, rolled %v", name, roll.Roll))
+			roll = nil
+		} else {
+			out = append(out, name)
+		}
+	}
+	for _, e := range events {
+		switch e := e.(type) {
+		case diceRolled:
+			flushRoll()
+			roll = &e
+		case coinTossed:
+			if e.Heads {
+				choice("Heads")
+			} else {
+				choice("Tails")
+			}
+		case directionSpun:
+			choice("Spun " + e.Direction)
+		case pieceMoved:
+			flushRoll()
+			out = append(out, fmt.Sprintf("%v at %v", e.Piece, e.To))
+		case piecePassed:
+			flushRoll()
+			out = append(out, fmt.Sprintf("%v passes", e.Piece))
+		case pieceCaptured:
+			// The outcome line already explains captures.
+		case boardShown:
+			flushRoll()
+			out = append(out, strings.Split(strings.TrimRight(e.Diagram, "\n"), "\n")...)
+		case gameEnded:
+			flushRoll()
+			out = append(out, e.String())
+		}
+	}
+	flushRoll()
+	return out
+}
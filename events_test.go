@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+func TestEvaluateProblemEvents(t *testing.T) {
+	got, err := evaluateProblem(&loadedCoin{Outcome: []bool{false, true}}, &loadedDice{Outcome: []int{3, 2}}, 2)
+	if err != nil {
+		t.Fatalf("evaluateProblem returned err %v", err)
+	}
+
+	want := []event{
+		diceRolled{Turn: 1, Roll: 3},
+		coinTossed{Turn: 1, Heads: false},
+		pieceMoved{Turn: 1, Piece: "Black Rook", From: "h1", To: "c1"},
+		diceRolled{Turn: 2, Roll: 2},
+		coinTossed{Turn: 2, Heads: true},
+		pieceCaptured{Turn: 2, Piece: "White Bishop", By: "Black Rook", Square: "c3"},
+		pieceMoved{Turn: 2, Piece: "Black Rook", From: "c1", To: "c3"},
+		gameEnded{Turn: 2, Winner: internal.BLACK, Reason: rookTakesBishop},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("evaluateProblem = %v, wanted %v", got, want)
+	}
+}
+
+func TestEvaluateProblemEventsStayingPut(t *testing.T) {
+	// Tails and a roll of 8 carry the Rook all the way around to h1.
+	got, err := evaluateProblem(&loadedCoin{Outcome: []bool{false}}, &loadedDice{Outcome: []int{8}}, 1)
+	if err != nil {
+		t.Fatalf("evaluateProblem returned err %v", err)
+	}
+
+	want := []event{
+		diceRolled{Turn: 1, Roll: 8},
+		coinTossed{Turn: 1, Heads: false},
+		pieceMoved{Turn: 1, Piece: "Black Rook", From: "h1", To: "h1"},
+		gameEnded{Turn: 1, Winner: internal.BLACK, Reason: rookEscapes},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("evaluateProblem = %v, wanted %v", got, want)
+	}
+}
+
+var renderTextTestCases = []struct {
+	events []event
+	want   []string
+}{
+	{
+		[]event{diceRolled{Turn: 1, Roll: 4}, directionSpun{Turn: 1, Direction: "left"}, pieceMoved{Turn: 1, Piece: "White Queen", From: "e4", To: "a4"}},
+		[]string{"Spun left, rolled 4", "White Queen at a4"},
+	},
+	{
+		[]event{diceRolled{Turn: 1, Roll: 4}, pieceMoved{Turn: 1, Piece: "White Queen", From: "e4", To: "e8"}},
+		[]string{"Rolled 4", "White Queen at e8"},
+	},
+	{
+		[]event{coinTossed{Turn: 1, Heads: true}, pieceMoved{Turn: 1, Piece: "White Queen", From: "e4", To: "e5"}},
+		[]string{"Heads", "White Queen at e5"},
+	},
+	{
+		[]event{gameEnded{Turn: 3, Winner: internal.EMPTY, Reason: "Turn limit reached"}},
+		[]string{"Turn limit reached, nobody wins"},
+	},
+	{
+		[]event{gameEnded{Turn: 3, Winner: internal.WHITE, Reason: bishopTakesRook}},
+		[]string{"Bishop can take rook, White wins"},
+	},
+}
+
+func TestRenderText(t *testing.T) {
+	for _, tc := range renderTextTestCases {
+		if got := renderText(tc.events); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("renderText(%v) = %v, wanted %v", tc.events, got, tc.want)
+		}
+	}
+}
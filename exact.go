@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+
+	"github.com/Techbert08/ChessProblem/internal"
+	"github.com/Techbert08/ChessProblem/internal/randomizer"
+)
+
+// twoDiceDistribution returns the exact probability of each sum of two six
+// sided dice.
+func twoDiceDistribution() map[int]*big.Rat {
+	out := make(map[int]*big.Rat)
+	for _, o := range randomizer.TwoD6().PMF() {
+		out[o.Value] = o.P
+	}
+	return out
+}
+
+// problemModel precomputes what the exact solver needs to know about the
+// board: where the Bishop sits and which squares it attacks.
+type problemModel struct {
+	// board holds only the Bishop, for stepping the Rook around.
+	board *internal.Board
+
+	// bishop is the Bishop's square.
+	bishop internal.Position
+
+	// attacked holds each square on which the Bishop could take the Rook.
+	attacked map[internal.Position]bool
+}
+
+// newProblemModel builds a problemModel with the White Bishop on bishopSquare.
+func newProblemModel(bishopSquare string) (*problemModel, error) {
+	board := internal.NewBoard()
+	if err := board.PlacePiece(internal.NewBishop(internal.WHITE), bishopSquare); err != nil {
+		return nil, err
+	}
+	bishop, err := internal.NewPosition(bishopSquare)
+	if err != nil {
+		return nil, err
+	}
+	attacked := make(map[internal.Position]bool)
+	for _, sq := range board.Squares() {
+		if sq == *bishop {
+			continue
+		}
+		// Place the Rook on its own board so it can block the Bishop exactly as
+		// it would in play.
+		b := internal.NewBoard()
+		if err := b.PlacePiece(internal.NewBishop(internal.WHITE), bishopSquare); err != nil {
+			return nil, err
+		}
+		if err := b.PlacePiece(internal.NewRook(internal.BLACK), sq.String()); err != nil {
+			return nil, err
+		}
+		attacked[sq] = b.IsAttacked(sq, internal.WHITE)
+	}
+	return &problemModel{
+		board:    board,
+		bishop:   *bishop,
+		attacked: attacked,
+	}, nil
+}
+
+// exactResult holds the exact probability of each outcome.
+type exactResult struct {
+	// byTurn holds, for each turn starting from the first, the probability
+	// that the game ends on that turn for each reason.
+	byTurn []map[string]*big.Rat
+
+	// total is the probability of each reason over the whole game.
+	total map[string]*big.Rat
+}
+
+func newExactResult(numMoves int) *exactResult {
+	r := &exactResult{
+		byTurn: make([]map[string]*big.Rat, numMoves),
+		total:  make(map[string]*big.Rat),
+	}
+	for i := range r.byTurn {
+		r.byTurn[i] = make(map[string]*big.Rat)
+		for _, o := range outcomes {
+			r.byTurn[i][o.Reason] = new(big.Rat)
+		}
+	}
+	for _, o := range outcomes {
+		r.total[o.Reason] = new(big.Rat)
+	}
+	return r
+}
+
+// addOutcome adds probability p of the game ending on turn for reason.
+func (r *exactResult) addOutcome(turn int, reason string, p *big.Rat) {
+	r.byTurn[turn][reason].Add(r.byTurn[turn][reason], p)
+	r.total[reason].Add(r.total[reason], p)
+}
+
+// report formats the result as human readable lines.
+func (r *exactResult) report() []string {
+	out := make([]string, 0)
+	for _, o := range outcomes {
+		f, _ := r.total[o.Reason].Float64()
+		out = append(out, fmt.Sprintf("%v: %v (%.6f)", o, r.total[o.Reason].RatString(), f))
+	}
+	for i, turn := range r.byTurn {
+		out = append(out, fmt.Sprintf("Turn %v:", i+1))
+		for _, o := range outcomes {
+			out = append(out, fmt.Sprintf("  %v: %v", o, turn[o.Reason].RatString()))
+		}
+	}
+	return out
+}
+
+// solveExact computes the exact probability of each outcome of p by
+// propagating the probability distribution of the Rook's square through each
+// turn of a fair coin and two dice.
+func solveExact(p problem) (*exactResult, error) {
+	model, err := newProblemModel(p.bishop)
+	if err != nil {
+		return nil, err
+	}
+	start, err := internal.NewPosition(p.rook)
+	if err != nil {
+		return nil, err
+	}
+	result := newExactResult(p.moves)
+	dice := twoDiceDistribution()
+	pmf := randomizer.FairCoin().PMF()
+	heads, tails := directions[p.heads], directions[p.tails]
+	coinSteps := []struct {
+		p                *big.Rat
+		perFile, perRank int
+	}{
+		{pmf[1].P, heads[0], heads[1]},
+		{pmf[0].P, tails[0], tails[1]},
+	}
+	dist := map[internal.Position]*big.Rat{*start: big.NewRat(1, 1)}
+	for turn := 0; turn < p.moves; turn++ {
+		next := make(map[internal.Position]*big.Rat)
+		for sq, pSq := range dist {
+			for roll, pRoll := range dice {
+				for _, step := range coinSteps {
+					dest, ok := model.board.Step(sq, step.perFile*roll, step.perRank*roll)
+					if !ok {
+						return nil, fmt.Errorf("rook cannot move off the board from %v", sq)
+					}
+					pMove := new(big.Rat).Mul(pSq, pRoll)
+					pMove.Mul(pMove, step.p)
+					switch {
+					case dest == model.bishop:
+						result.addOutcome(turn, rookTakesBishop, pMove)
+					case model.attacked[dest]:
+						result.addOutcome(turn, bishopTakesRook, pMove)
+					default:
+						if next[dest] == nil {
+							next[dest] = new(big.Rat)
+						}
+						next[dest].Add(next[dest], pMove)
+					}
+				}
+			}
+		}
+		dist = next
+	}
+	for _, pSq := range dist {
+		result.addOutcome(p.moves-1, rookEscapes, pSq)
+	}
+	return result, nil
+}
+
+// runExact implements the exact subcommand.
+func runExact(args []string) error {
+	fs := flag.NewFlagSet("exact", flag.ContinueOnError)
+	p := defaultProblem()
+	p.addFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	result, err := solveExact(p)
+	if err != nil {
+		return err
+	}
+	for _, l := range result.report() {
+		fmt.Println(l)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestTwoDiceDistribution(t *testing.T) {
+	got := twoDiceDistribution()
+	want := map[int]int64{2: 1, 3: 2, 4: 3, 5: 4, 6: 5, 7: 6, 8: 5, 9: 4, 10: 3, 11: 2, 12: 1}
+	if len(got) != len(want) {
+		t.Errorf("twoDiceDistribution() = %v, wanted %v sums", got, len(want))
+	}
+	for roll, ways := range want {
+		if p := got[roll]; p == nil || p.Cmp(big.NewRat(ways, 36)) != 0 {
+			t.Errorf("twoDiceDistribution()[%v] = %v, wanted %v/36", roll, p, ways)
+		}
+	}
+}
+
+func TestSolveExactOneMove(t *testing.T) {
+	got, err := solveExact(movesProblem(1))
+	if err != nil {
+		t.Fatalf("solveExact(1) returned err %v", err)
+	}
+	// Heads lands on h6 or h8 with 4+6 ways, tails on e1 or a1 with 4+4 ways.
+	want := map[string]*big.Rat{
+		rookTakesBishop: big.NewRat(0, 1),
+		bishopTakesRook: big.NewRat(1, 4),
+		rookEscapes:     big.NewRat(3, 4),
+	}
+	for _, o := range outcomes {
+		if got.total[o.Reason].Cmp(want[o.Reason]) != 0 {
+			t.Errorf("solveExact(1) %v = %v, wanted %v", o, got.total[o.Reason], want[o.Reason])
+		}
+		if got.byTurn[0][o.Reason].Cmp(want[o.Reason]) != 0 {
+			t.Errorf("solveExact(1) turn 1 %v = %v, wanted %v", o, got.byTurn[0][o.Reason], want[o.Reason])
+		}
+	}
+}
+
+func TestSolveExactSumsToOne(t *testing.T) {
+	got, err := solveExact(movesProblem(15))
+	if err != nil {
+		t.Fatalf("solveExact(15) returned err %v", err)
+	}
+	total := new(big.Rat)
+	byTurn := new(big.Rat)
+	for _, o := range outcomes {
+		total.Add(total, got.total[o.Reason])
+		for _, turn := range got.byTurn {
+			byTurn.Add(byTurn, turn[o.Reason])
+		}
+	}
+	if total.Cmp(big.NewRat(1, 1)) != 0 || byTurn.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("solveExact(15) totals %v and per turn sums %v, wanted 1", total, byTurn)
+	}
+	// Only the last turn can end in escape.
+	for i, turn := range got.byTurn[:14] {
+		if turn[rookEscapes].Sign() != 0 {
+			t.Errorf("solveExact(15) turn %v escape = %v, wanted 0", i+1, turn[rookEscapes])
+		}
+	}
+	// Tails 3 then Heads 2 takes the Bishop, so turn 2 must allow it.
+	if got.byTurn[1][rookTakesBishop].Sign() <= 0 {
+		t.Errorf("solveExact(15) turn 2 capture = %v, wanted positive", got.byTurn[1][rookTakesBishop])
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"image/gif"
+	"os"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// Frame delays in hundredths of a second.
+const (
+	gifMoveDelay = 120
+	gifEndDelay  = 400
+)
+
+// gameGIF animates a game of p described by events, with a frame for the
+// starting position and one after each move.  Each frame is captioned with
+// the coin and dice that decided the move and highlights the squares the
+// Bishop attacks.  The last frame holds longer and names the outcome.
+func gameGIF(p problem, events []event) (*gif.GIF, error) {
+	board := internal.NewBoard()
+	if err := board.PlacePiece(internal.NewRook(internal.BLACK), p.rook); err != nil {
+		return nil, err
+	}
+	if err := board.PlacePiece(internal.NewBishop(internal.WHITE), p.bishop); err != nil {
+		return nil, err
+	}
+	anim := &gif.GIF{}
+	addFrame := func(caption string, delay int) {
+		img := board.RenderImage(internal.ImageOptions{AttackedBy: internal.WHITE, Caption: caption})
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	addFrame(fmt.Sprintf("Rook %v, Bishop %v", p.rook, p.bishop), gifMoveDelay)
+	roll, toss := 0, ""
+	for _, e := range events {
+		switch e := e.(type) {
+		case diceRolled:
+			roll = e.Roll
+		case coinTossed:
+			toss = "Tails"
+			if e.Heads {
+				toss = "Heads"
+			}
+		case pieceMoved:
+			from, err := internal.NewPosition(e.From)
+			if err != nil {
+				return nil, err
+			}
+			to, err := internal.NewPosition(e.To)
+			if err != nil {
+				return nil, err
+			}
+			if err := board.MovePiece(board.GetPieceAtPosition(*from), *to); err != nil {
+				return nil, err
+			}
+			addFrame(fmt.Sprintf("Turn %v: %v, rolled %v", e.Turn, toss, roll), gifMoveDelay)
+		case gameEnded:
+			addFrame(e.String(), gifEndDelay)
+		}
+	}
+	return anim, nil
+}
+
+// writeGameGIF writes gameGIF's animation of a game of p to path.
+func writeGameGIF(path string, p problem, events []event) error {
+	anim, err := gameGIF(p, events)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
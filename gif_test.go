@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGameGIF(t *testing.T) {
+	// Tails 3 takes the Rook from h1 around the edge to c1, then Heads 2 takes
+	// the Bishop on c3.
+	events, err := replay(defaultProblem(), []bool{false, true}, []int{3, 2})
+	if err != nil {
+		t.Fatalf("replay returned err %v", err)
+	}
+	got, err := gameGIF(defaultProblem(), events)
+	if err != nil {
+		t.Fatalf("gameGIF returned err %v", err)
+	}
+	// The start, two moves and the outcome.
+	if len(got.Image) != 4 || len(got.Delay) != 4 {
+		t.Fatalf("gameGIF made %v frames and %v delays, wanted 4", len(got.Image), len(got.Delay))
+	}
+	if got.Delay[3] != gifEndDelay {
+		t.Errorf("gameGIF last delay = %v, wanted %v", got.Delay[3], gifEndDelay)
+	}
+	for i, img := range got.Image[1:] {
+		if img.Bounds() != got.Image[0].Bounds() {
+			t.Errorf("frame %v is %v, wanted %v like the first", i+1, img.Bounds(), got.Image[0].Bounds())
+		}
+	}
+}
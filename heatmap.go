@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// heatmapOutcomes maps each -outcome flag value to the reason a heatmap
+// shades.
+var heatmapOutcomes = map[string]string{
+	"captured": bishopTakesRook,
+	"captures": rookTakesBishop,
+}
+
+// heatmap holds the exact chance of each outcome of a problem with one piece
+// started on every square in turn and the other left where the problem puts
+// it.
+type heatmap struct {
+	// p is the problem the starting squares are varied from.
+	p problem
+
+	// vary names the piece moved around the board, "rook" or "bishop".
+	vary string
+
+	// squares holds the probability of each reason by the varied piece's
+	// square.  The other piece's square is missing.
+	squares map[internal.Position]map[string]*big.Rat
+}
+
+// buildHeatmap solves p exactly with the piece named by vary started on each
+// square not held by the other piece.
+func buildHeatmap(p problem, vary string) (*heatmap, error) {
+	if vary != "rook" && vary != "bishop" {
+		return nil, fmt.Errorf("vary should be rook or bishop, got %q", vary)
+	}
+	h := &heatmap{
+		p:       p,
+		vary:    vary,
+		squares: make(map[internal.Position]map[string]*big.Rat),
+	}
+	for _, sq := range internal.NewBoard().Squares() {
+		q := h.squareProblem(sq)
+		if q.rook == q.bishop {
+			continue
+		}
+		result, err := solveExact(q)
+		if err != nil {
+			return nil, err
+		}
+		h.squares[sq] = result.total
+	}
+	return h, nil
+}
+
+// fixed returns the letter and square of the piece left in place.
+func (h *heatmap) fixed() (rune, string) {
+	if h.vary == "rook" {
+		return 'B', h.p.bishop
+	}
+	return 'R', h.p.rook
+}
+
+// squareProblem returns the problem with the varied piece on sq.
+func (h *heatmap) squareProblem(sq internal.Position) problem {
+	q := h.p
+	if h.vary == "rook" {
+		q.rook = sq.String()
+	} else {
+		q.bishop = sq.String()
+	}
+	return q
+}
+
+// probability returns the chance of reason with the varied piece on sq.
+func (h *heatmap) probability(sq internal.Position, reason string) float64 {
+	f, _ := h.squares[sq][reason].Float64()
+	return f
+}
+
+// grid draws the chance of reason as a grid of percentages with the highest
+// rank at the top, marking the fixed piece's square with its letter.
+func (h *heatmap) grid(reason string) []string {
+	letter, square := h.fixed()
+	outcome := gameEnded{Reason: reason}
+	for _, o := range outcomes {
+		if o.Reason == reason {
+			outcome = o
+		}
+	}
+	name := map[string]string{"rook": "Rook", "bishop": "Bishop"}
+	other := map[rune]string{'B': "Bishop", 'R': "Rook"}
+	out := []string{fmt.Sprintf("%v, by the %v's starting square with the %v on %v:", outcome, name[h.vary], other[letter], square)}
+	return append(out, squareGrid(internal.NewBoard(), 6, func(sq internal.Position) string {
+		if _, ok := h.squares[sq]; !ok {
+			return string(letter)
+		}
+		return fmt.Sprintf("%.1f%%", 100*h.probability(sq, reason))
+	})...)
+}
+
+// heatmapOutcome is the probability of one outcome from a pair of starting
+// squares.
+type heatmapOutcome struct {
+	Reason      string  `json:"reason"`
+	Winner      string  `json:"winner"`
+	Probability float64 `json:"probability"`
+}
+
+// heatmapSquare lists every outcome's probability for one pair of starting
+// squares.
+type heatmapSquare struct {
+	Rook     string           `json:"rook"`
+	Bishop   string           `json:"bishop"`
+	Outcomes []heatmapOutcome `json:"outcomes"`
+}
+
+// rows lists the probability of every outcome for each square in board
+// order, skipping the fixed piece's square.
+func (h *heatmap) rows() []heatmapSquare {
+	out := make([]heatmapSquare, 0, len(h.squares))
+	for _, sq := range internal.NewBoard().Squares() {
+		if _, ok := h.squares[sq]; !ok {
+			continue
+		}
+		q := h.squareProblem(sq)
+		row := heatmapSquare{Rook: q.rook, Bishop: q.bishop}
+		for _, o := range outcomes {
+			row.Outcomes = append(row.Outcomes, heatmapOutcome{
+				Reason:      o.Reason,
+				Winner:      winnerName(o.Winner),
+				Probability: h.probability(sq, o.Reason),
+			})
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// writeHeatmap writes h to w in format.  Text draws the grid for reason,
+// while JSON and CSV list every outcome for every square.
+func writeHeatmap(w io.Writer, format string, h *heatmap, reason string) error {
+	switch format {
+	case "text":
+		for _, l := range h.grid(reason) {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		return writeJSON(w, struct {
+			Vary    string          `json:"vary"`
+			Moves   int             `json:"moves"`
+			Squares []heatmapSquare `json:"squares"`
+		}{h.vary, h.p.moves, h.rows()})
+	case "csv":
+		cw := csv.NewWriter(w)
+		rows := [][]string{{"rook", "bishop", "reason", "winner", "probability"}}
+		for _, s := range h.rows() {
+			for _, o := range s.Outcomes {
+				rows = append(rows, []string{s.Rook, s.Bishop, o.Reason, o.Winner, formatFloat(o.Probability)})
+			}
+		}
+		return cw.WriteAll(rows)
+	}
+	return checkFormat(format)
+}
+
+// heatmapSVG draws the chance of reason as an SVG board, shading each
+// square and labelling it with the percentage, with the fixed piece in place.
+func heatmapSVG(h *heatmap, reason string) (string, error) {
+	board := internal.NewBoard()
+	letter, square := h.fixed()
+	piece := internal.ChessPiece(internal.NewBishop(internal.WHITE))
+	if letter == 'R' {
+		piece = internal.NewRook(internal.BLACK)
+	}
+	if err := board.PlacePiece(piece, square); err != nil {
+		return "", err
+	}
+	opts := internal.SVGOptions{
+		Heat:   make(map[internal.Position]float64),
+		Labels: make(map[internal.Position]string),
+	}
+	for sq := range h.squares {
+		p := h.probability(sq, reason)
+		opts.Heat[sq] = p
+		opts.Labels[sq] = fmt.Sprintf("%.1f%%", 100*p)
+	}
+	return board.RenderSVG(opts), nil
+}
+
+// runHeatmap implements the heatmap subcommand.
+func runHeatmap(args []string) error {
+	fs := flag.NewFlagSet("heatmap", flag.ContinueOnError)
+	vary := fs.String("vary", "rook", "piece to start on every square: rook or bishop")
+	outcome := fs.String("outcome", "captured", "chance to map: captured for the Rook being taken, captures for the Rook taking the Bishop")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	svgPath := fs.String("svg", "", "also draw the map as an SVG image to this file")
+	p := defaultProblem()
+	p.addFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	reason, ok := heatmapOutcomes[*outcome]
+	if !ok {
+		return fmt.Errorf("outcome should be captured or captures, got %q", *outcome)
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	h, err := buildHeatmap(p, *vary)
+	if err != nil {
+		return err
+	}
+	if *svgPath != "" {
+		svg, err := heatmapSVG(h, reason)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*svgPath, []byte(svg), 0644); err != nil {
+			return err
+		}
+	}
+	return writeHeatmap(os.Stdout, *format, h, reason)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+func mustHeatmap(t *testing.T, p problem, vary string) *heatmap {
+	t.Helper()
+	h, err := buildHeatmap(p, vary)
+	if err != nil {
+		t.Fatalf("buildHeatmap(%v) returned err %v", vary, err)
+	}
+	return h
+}
+
+func TestBuildHeatmap(t *testing.T) {
+	testCases := []struct {
+		vary   string
+		square string
+		reason string
+		want   *big.Rat
+	}{
+		// One move from h1 is the whole problem: see TestSolveExactOneMove.
+		{"rook", "h1", bishopTakesRook, big.NewRat(1, 4)},
+		{"rook", "h1", rookTakesBishop, big.NewRat(0, 1)},
+		// The Rook on h1 lands on h3 with heads and a roll of 2 or 10.
+		{"bishop", "h3", rookTakesBishop, big.NewRat(1, 18)},
+	}
+	for _, tc := range testCases {
+		h := mustHeatmap(t, movesProblem(1), tc.vary)
+		if len(h.squares) != 63 {
+			t.Errorf("buildHeatmap(%v) solved %v squares, wanted 63", tc.vary, len(h.squares))
+		}
+		sq, _ := internal.NewPosition(tc.square)
+		if got := h.squares[*sq][tc.reason]; got == nil || got.Cmp(tc.want) != 0 {
+			t.Errorf("buildHeatmap(%v) on %v: %v = %v, wanted %v", tc.vary, tc.square, tc.reason, got, tc.want)
+		}
+	}
+	if _, err := buildHeatmap(movesProblem(1), "queen"); err == nil {
+		t.Errorf("buildHeatmap(queen) returned no error")
+	}
+}
+
+func TestHeatmapGrid(t *testing.T) {
+	h := mustHeatmap(t, movesProblem(1), "rook")
+	got := h.grid(bishopTakesRook)
+	if len(got) != 10 {
+		t.Fatalf("grid() drew %v lines, wanted 10:\n%v", len(got), strings.Join(got, "\n"))
+	}
+	if want := "Bishop can take rook, White wins, by the Rook's starting square with the Bishop on c3:"; got[0] != want {
+		t.Errorf("grid() title = %q, wanted %q", got[0], want)
+	}
+	if want := "       a      b      c      d      e      f      g      h"; got[9] != want {
+		t.Errorf("grid() files = %q, wanted %q", got[9], want)
+	}
+	if !strings.HasPrefix(got[8], "1 ") || !strings.HasSuffix(got[8], " 25.0%") {
+		t.Errorf("grid() rank 1 = %q, wanted 25.0%% on h1 last", got[8])
+	}
+	if !strings.Contains(got[6], "      B") {
+		t.Errorf("grid() rank 3 = %q, wanted the Bishop on c3", got[6])
+	}
+}
+
+func TestWriteHeatmap(t *testing.T) {
+	h := mustHeatmap(t, movesProblem(1), "bishop")
+
+	var buf bytes.Buffer
+	if err := writeHeatmap(&buf, "csv", h, rookTakesBishop); err != nil {
+		t.Fatalf("writeHeatmap(csv) returned err %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("writeHeatmap(csv) wrote bad CSV: %v", err)
+	}
+	if len(rows) != 1+63*len(outcomes) {
+		t.Errorf("writeHeatmap(csv) wrote %v rows, wanted %v", len(rows), 1+63*len(outcomes))
+	}
+	// Tails and a roll of 9 take the Rook around to a1.
+	if got, want := strings.Join(rows[1], ","), "h1,a1,Rook takes bishop,Black,0.05555555555555555"; got != want {
+		t.Errorf("writeHeatmap(csv) first row = %q, wanted %q", got, want)
+	}
+
+	buf.Reset()
+	if err := writeHeatmap(&buf, "json", h, rookTakesBishop); err != nil {
+		t.Fatalf("writeHeatmap(json) returned err %v", err)
+	}
+	var decoded struct {
+		Vary    string
+		Moves   int
+		Squares []heatmapSquare
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("writeHeatmap(json) wrote bad JSON: %v", err)
+	}
+	if decoded.Vary != "bishop" || decoded.Moves != 1 || len(decoded.Squares) != 63 {
+		t.Errorf("writeHeatmap(json) = %+v, wanted 63 bishop squares over 1 move", decoded)
+	}
+
+	if err := writeHeatmap(&buf, "xml", h, rookTakesBishop); err == nil {
+		t.Errorf("writeHeatmap(xml) returned no error")
+	}
+}
+
+func TestHeatmapSVG(t *testing.T) {
+	h := mustHeatmap(t, movesProblem(1), "rook")
+	got, err := heatmapSVG(h, bishopTakesRook)
+	if err != nil {
+		t.Fatalf("heatmapSVG returned err %v", err)
+	}
+	if n := strings.Count(got, "%</text>"); n != 63 {
+		t.Errorf("heatmapSVG labelled %v squares, wanted 63", n)
+	}
+	if !strings.Contains(got, "♗") {
+		t.Errorf("heatmapSVG did not draw the Bishop:\n%v", got)
+	}
+}
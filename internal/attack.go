@@ -0,0 +1,92 @@
+package internal
+
+// attacks returns true if piece could capture an enemy piece standing on
+// pos.  Pawns attack diagonally forward whatever stands there now, while
+// every other piece attacks the squares it could move to.  No piece attacks
+// a square held by its own side.
+func (b *Board) attacks(piece ChessPiece, pos Position) bool {
+	from := piece.GetPosition()
+	if from == nil || *from == pos {
+		return false
+	}
+	p, ok := piece.(*Pawn)
+	if !ok {
+		return piece.IsLegalMove(pos)
+	}
+	if !p.checkDestination(pos) {
+		return false
+	}
+	return p.checkStep(pos, 1, p.forward()) || p.checkStep(pos, -1, p.forward())
+}
+
+// IsAttacked returns true if any piece of Color c could capture on pos.
+// Attacks across wrapped edges count.
+func (b *Board) IsAttacked(pos Position, c Color) bool {
+	// Walk the map directly rather than through Pieces, as this runs after
+	// every move and the order does not matter.
+	for _, piece := range b.positions {
+		if piece.GetColor() == c && b.attacks(piece, pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// Attackers returns every piece of Color c that could capture on pos,
+// ordered by position.  Pieces pinned to their own King are included, as
+// they still give check.
+func (b *Board) Attackers(pos Position, c Color) []ChessPiece {
+	out := make([]ChessPiece, 0)
+	for _, piece := range b.Pieces(c) {
+		if b.attacks(piece, pos) {
+			out = append(out, piece)
+		}
+	}
+	return out
+}
+
+// AttackedSquares returns the squares pieces of Color c could capture on,
+// whether or not an enemy piece stands there now, ordered by rank and then
+// file.  Squares held by c's own pieces are not included.
+func (b *Board) AttackedSquares(c Color) []Position {
+	if g := b.bits; g != nil {
+		attacked := bitboard(0)
+		for _, piece := range b.positions {
+			if piece.GetColor() == c {
+				attacked |= b.attackBits(piece)
+			}
+		}
+		return g.positions(attacked &^ b.byColor[c])
+	}
+	out := make([]Position, 0)
+	for _, sq := range b.Squares() {
+		if b.IsAttacked(sq, c) {
+			out = append(out, sq)
+		}
+	}
+	return out
+}
+
+// attackBits returns the squares piece attacks as a bitboard, including any
+// held by its own side.  The board must have bitboards.
+func (b *Board) attackBits(piece ChessPiece) bitboard {
+	g := b.bits
+	from := *piece.GetPosition()
+	var out bitboard
+	switch p := piece.(type) {
+	case *Rook:
+		out = g.rays(from, rookDirections, b.occupied)
+	case *Bishop:
+		out = g.rays(from, bishopDirections, b.occupied)
+	case *Queen:
+		out = g.rays(from, kingOffsets, b.occupied)
+	case *Knight:
+		out = g.steps(from, knightOffsets)
+	case *King:
+		out = g.steps(from, kingOffsets)
+	case *Pawn:
+		out = g.steps(from, [][2]int{{1, p.forward()}, {-1, p.forward()}})
+	}
+	// On a tiny wrapping board a step can land back where it started.
+	return out &^ g.bit(from)
+}
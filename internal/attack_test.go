@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestAttackedSquaresPawnWraps(t *testing.T) {
+	b := NewBoard()
+	mustPlace(t, b, NewPawn(WHITE), "a2")
+	// The Pawn attacks b3 and, around the edge, h3 but not its push to a3.
+	want := []Position{mustPosition(t, "b3"), mustPosition(t, "h3")}
+	if got := b.AttackedSquares(WHITE); !reflect.DeepEqual(got, want) {
+		t.Errorf("AttackedSquares(WHITE) = %v, wanted %v", got, want)
+	}
+}
+
+func TestAttackedSquaresSkipsOwnPieces(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	mustPlace(t, b, NewRook(WHITE), "a1")
+	mustPlace(t, b, NewPawn(WHITE), "a2")
+	mustPlace(t, b, NewKnight(WHITE), "b1")
+	// The Rook is hemmed in, so only the Pawn and Knight attack anything.
+	want := []Position{
+		mustPosition(t, "d2"),
+		mustPosition(t, "a3"), mustPosition(t, "b3"), mustPosition(t, "c3"),
+	}
+	if got := b.AttackedSquares(WHITE); !reflect.DeepEqual(got, want) {
+		t.Errorf("AttackedSquares(WHITE) = %v, wanted %v", got, want)
+	}
+}
+
+func TestAttackers(t *testing.T) {
+	b := NewBoard()
+	rook := NewRook(BLACK)
+	mustPlace(t, b, rook, "h1")
+	bishop := NewBishop(WHITE)
+	mustPlace(t, b, bishop, "c3")
+	knight := NewKnight(WHITE)
+	mustPlace(t, b, knight, "f2")
+	pawn := NewPawn(WHITE)
+	mustPlace(t, b, pawn, "a8")
+	testCases := []struct {
+		square string
+		color  Color
+		want   []ChessPiece
+	}{
+		// The Bishop reaches h8 along its long diagonal, while the Knight and
+		// the Pawn reach h1 directly and around the corner.
+		{"h1", WHITE, []ChessPiece{knight, pawn}},
+		{"h8", WHITE, []ChessPiece{bishop}},
+		// The Pawn attacks b1 around the top edge, and the Rook reaches it
+		// around the right edge.
+		{"b1", WHITE, []ChessPiece{pawn}},
+		{"b1", BLACK, []ChessPiece{rook}},
+		// No piece attacks the square it stands on.
+		{"c3", WHITE, []ChessPiece{}},
+		{"a1", BLACK, []ChessPiece{rook}},
+	}
+	for _, tc := range testCases {
+		pos := mustPosition(t, tc.square)
+		got := b.Attackers(pos, tc.color)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Attackers(%v, %v) = %v, wanted %v", tc.square, tc.color, got, tc.want)
+		}
+		if attacked := b.IsAttacked(pos, tc.color); attacked != (len(tc.want) > 0) {
+			t.Errorf("IsAttacked(%v, %v) = %v, wanted %v", tc.square, tc.color, attacked, len(tc.want) > 0)
+		}
+	}
+}
+
+func TestAttackedSquaresMatchesAttackers(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, topology := range []Topology{STANDARD, HORIZONTAL_CYLINDER, VERTICAL_CYLINDER, TORUS} {
+		for _, shape := range [][2]int{{8, 8}, {3, 5}, {1, 6}, {12, 8}} {
+			fast, slow := randomBoards(t, rng, shape[0], shape[1], topology)
+			for _, c := range []Color{WHITE, BLACK} {
+				want := make([]Position, 0)
+				for _, sq := range fast.Squares() {
+					if len(fast.Attackers(sq, c)) > 0 {
+						want = append(want, sq)
+					}
+				}
+				if got := fast.AttackedSquares(c); !reflect.DeepEqual(got, want) {
+					t.Errorf("%vx%v %v: AttackedSquares(%v) = %v, wanted %v\n%v", shape[0], shape[1], topology, c, got, want, fast.Render(RenderOptions{}))
+				}
+				if got := slow.AttackedSquares(c); !reflect.DeepEqual(got, want) {
+					t.Errorf("%vx%v %v without bitboards: AttackedSquares(%v) = %v, wanted %v", shape[0], shape[1], topology, c, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"math/bits"
+)
+
+// bitboard is a set of squares on a board of at most 64 squares, with the
+// square at file f and rank r held in bit r*width+f.  Ranks are stored from
+// the bottom, so iterating bits from the lowest visits squares in the order
+// sortPositions uses.
+type bitboard uint64
+
+// bitGeometry holds what is needed to shift bitboards for one board shape
+// and Topology.
+type bitGeometry struct {
+	width, height int
+	topology      Topology
+
+	// all holds every square, and firstFile, lastFile, firstRank and
+	// lastRank the squares along each edge.
+	all                 bitboard
+	firstFile, lastFile bitboard
+	firstRank, lastRank bitboard
+}
+
+// newBitGeometry returns the bitGeometry for a board width files wide and
+// height ranks tall, or nil if the board has more than 64 squares.
+func newBitGeometry(width, height int, t Topology) *bitGeometry {
+	if width*height > 64 {
+		return nil
+	}
+	g := &bitGeometry{width: width, height: height, topology: t}
+	g.all = ^bitboard(0) >> (64 - width*height)
+	for rank := 0; rank < height; rank++ {
+		g.firstFile |= 1 << (rank * width)
+		g.lastFile |= 1 << (rank*width + width - 1)
+	}
+	g.firstRank = ^bitboard(0) >> (64 - width)
+	g.lastRank = g.firstRank << ((height - 1) * width)
+	return g
+}
+
+// bit returns the bitboard holding only p.
+func (g *bitGeometry) bit(p Position) bitboard {
+	return 1 << (p.rank*g.width + p.file)
+}
+
+// position returns the square held in bit i.
+func (g *bitGeometry) position(i int) Position {
+	return Position{rank: i / g.width, file: i % g.width}
+}
+
+// positions lists the squares in bb ordered by rank and then file.
+func (g *bitGeometry) positions(bb bitboard) []Position {
+	out := make([]Position, 0, bits.OnesCount64(uint64(bb)))
+	for bb != 0 {
+		i := bits.TrailingZeros64(uint64(bb))
+		out = append(out, g.position(i))
+		bb &= bb - 1
+	}
+	return out
+}
+
+// shiftFile moves every square in bb one file right if right is set, or
+// left otherwise.  Squares pushed off an edge re-enter on the opposite edge
+// if files wrap, and are dropped if not.
+func (g *bitGeometry) shiftFile(bb bitboard, right bool) bitboard {
+	wraps := g.topology.wrapsFiles()
+	if right {
+		out := (bb &^ g.lastFile) << 1
+		if wraps {
+			out |= (bb & g.lastFile) >> (g.width - 1)
+		}
+		return out
+	}
+	out := (bb &^ g.firstFile) >> 1
+	if wraps {
+		out |= (bb & g.firstFile) << (g.width - 1)
+	}
+	return out
+}
+
+// shiftRank moves every square in bb one rank up if up is set, or down
+// otherwise, wrapping as the ranks allow.
+func (g *bitGeometry) shiftRank(bb bitboard, up bool) bitboard {
+	wraps := g.topology.wrapsRanks()
+	across := (g.height - 1) * g.width
+	if up {
+		out := (bb &^ g.lastRank) << g.width
+		if wraps {
+			out |= (bb & g.lastRank) >> across
+		}
+		return out
+	}
+	out := (bb &^ g.firstRank) >> g.width
+	if wraps {
+		out |= (bb & g.firstRank) << across
+	}
+	return out
+}
+
+// shift moves every square in bb f files and r ranks, one square at a time
+// so each edge is wrapped or dropped as the Topology says.
+func (g *bitGeometry) shift(bb bitboard, f, r int) bitboard {
+	for ; f > 0; f-- {
+		bb = g.shiftFile(bb, true)
+	}
+	for ; f < 0; f++ {
+		bb = g.shiftFile(bb, false)
+	}
+	for ; r > 0; r-- {
+		bb = g.shiftRank(bb, true)
+	}
+	for ; r < 0; r++ {
+		bb = g.shiftRank(bb, false)
+	}
+	return bb
+}
+
+// ray returns the squares reached by repeatedly stepping f files and r
+// ranks from p, including the first square in occupied, which ends the ray.
+// The ray also ends at an edge that does not wrap, or on wrapping back
+// around to p.
+func (g *bitGeometry) ray(p Position, f, r int, occupied bitboard) bitboard {
+	start := g.bit(p)
+	out := bitboard(0)
+	for cur := g.shift(start, f, r); cur != 0 && cur != start; cur = g.shift(cur, f, r) {
+		out |= cur
+		if cur&occupied != 0 {
+			break
+		}
+	}
+	return out
+}
+
+// rays returns the union of the rays from p along each of directions.
+func (g *bitGeometry) rays(p Position, directions [][2]int, occupied bitboard) bitboard {
+	out := bitboard(0)
+	for _, d := range directions {
+		out |= g.ray(p, d[0], d[1], occupied)
+	}
+	return out
+}
+
+// steps returns the squares a single (file, rank) offset away from p, for
+// each of offsets.
+func (g *bitGeometry) steps(p Position, offsets [][2]int) bitboard {
+	start := g.bit(p)
+	out := bitboard(0)
+	for _, o := range offsets {
+		out |= g.shift(start, o[0], o[1])
+	}
+	return out
+}
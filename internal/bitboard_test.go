@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBitGeometryShift(t *testing.T) {
+	testCases := []struct {
+		topology Topology
+		from     string
+		f, r     int
+		want     string
+	}{
+		{STANDARD, "d4", 1, 0, "e4"},
+		{STANDARD, "h4", 1, 0, ""},
+		{STANDARD, "a4", -1, 0, ""},
+		{STANDARD, "d8", 0, 1, ""},
+		{STANDARD, "d1", 0, -1, ""},
+		{HORIZONTAL_CYLINDER, "h4", 1, 0, "a4"},
+		{HORIZONTAL_CYLINDER, "a4", -1, 0, "h4"},
+		{HORIZONTAL_CYLINDER, "d8", 0, 1, ""},
+		{VERTICAL_CYLINDER, "d8", 0, 1, "d1"},
+		{VERTICAL_CYLINDER, "d1", 0, -1, "d8"},
+		{VERTICAL_CYLINDER, "h4", 1, 0, ""},
+		{TORUS, "h8", 1, 1, "a1"},
+		{TORUS, "a1", -1, -1, "h8"},
+		{TORUS, "c3", -3, 9, "h4"},
+	}
+	for _, tc := range testCases {
+		g := newBitGeometry(8, 8, tc.topology)
+		from := mustPosition(t, tc.from)
+		got := g.positions(g.shift(g.bit(from), tc.f, tc.r))
+		want := []Position{}
+		if tc.want != "" {
+			want = []Position{mustPosition(t, tc.want)}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("shift(%v, %v, %v) on %v = %v, wanted %v", tc.from, tc.f, tc.r, tc.topology, got, want)
+		}
+	}
+}
+
+func TestNewBitGeometryTooLarge(t *testing.T) {
+	if g := newBitGeometry(9, 8, TORUS); g != nil {
+		t.Errorf("newBitGeometry(9, 8) = %+v, wanted nil for more than 64 squares", g)
+	}
+	if g := newBitGeometry(8, 8, TORUS); g == nil || g.all != ^bitboard(0) {
+		t.Errorf("newBitGeometry(8, 8) = %+v, wanted every bit set in all", g)
+	}
+}
+
+// randomBoards sets up the same random position on two boards of the given
+// shape, one using bitboards and one using only the positions map.
+func randomBoards(t *testing.T, rng *rand.Rand, width, height int, topology Topology) (fast, slow *Board) {
+	t.Helper()
+	fast, err := NewSizedBoard(width, height, topology)
+	if err != nil {
+		t.Fatalf("NewSizedBoard returned err %v", err)
+	}
+	slow, _ = NewSizedBoard(width, height, topology)
+	slow.bits = nil
+	letters := "RBQNKPrbqnkp"
+	for i := 0; i < width*height/4; i++ {
+		sq := Position{rank: rng.Intn(height), file: rng.Intn(width)}
+		if fast.GetPieceAtPosition(sq) != nil {
+			continue
+		}
+		letter := rune(letters[rng.Intn(len(letters))])
+		a, _ := NewPieceFromLetter(letter)
+		b, _ := NewPieceFromLetter(letter)
+		mustPlace(t, fast, a, sq.String())
+		mustPlace(t, slow, b, sq.String())
+	}
+	return fast, slow
+}
+
+func TestBitboardMatchesMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	shapes := [][2]int{{8, 8}, {5, 7}, {1, 6}, {6, 1}, {4, 16}}
+	for _, topology := range []Topology{STANDARD, HORIZONTAL_CYLINDER, VERTICAL_CYLINDER, TORUS} {
+		for _, shape := range shapes {
+			for trial := 0; trial < 20; trial++ {
+				fast, slow := randomBoards(t, rng, shape[0], shape[1], topology)
+				if fast.bits == nil {
+					t.Fatalf("%vx%v board has no bitboards", shape[0], shape[1])
+				}
+				for _, sq := range fast.Squares() {
+					fp, sp := fast.GetPieceAtPosition(sq), slow.GetPieceAtPosition(sq)
+					if fp == nil {
+						continue
+					}
+					if got, want := fp.LegalMoves(), sp.LegalMoves(); !reflect.DeepEqual(got, want) {
+						t.Fatalf("%v on %vx%v %v: LegalMoves() = %v, wanted %v\n%v", fp, shape[0], shape[1], topology, got, want, fast.Render(RenderOptions{}))
+					}
+					// Squares just off the board share bit indexes with real
+					// squares, so they must be refused explicitly.
+					offBoard := []Position{
+						{rank: 0, file: shape[0]},
+						{rank: 0, file: shape[0] + 2},
+						{rank: shape[1], file: 0},
+						{rank: shape[1] - 1, file: shape[0]},
+					}
+					for _, dest := range append(fast.Squares(), offBoard...) {
+						if got, want := fp.IsLegalMove(dest), sp.IsLegalMove(dest); got != want {
+							t.Fatalf("%v on %vx%v %v: IsLegalMove(%v) = %v, wanted %v\n%v", fp, shape[0], shape[1], topology, dest, got, want, fast.Render(RenderOptions{}))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBitboardFollowsMoves(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4k3/3p4/8/4P3/8/8/8/R3K3 b - - 0 1"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	check := func(when string) {
+		t.Helper()
+		want := [3]bitboard{}
+		occupied := bitboard(0)
+		for sq, piece := range b.positions {
+			want[piece.GetColor()] |= b.bits.bit(sq)
+			occupied |= b.bits.bit(sq)
+		}
+		if b.byColor != want || b.occupied != occupied {
+			t.Errorf("%v: bitboards %x %v, wanted %x %v", when, b.occupied, b.byColor, occupied, want)
+		}
+	}
+	check("after FromFEN")
+	pawn := b.GetPieceAtPosition(mustPosition(t, "d7"))
+	if err := b.MovePiece(pawn, mustPosition(t, "d5")); err != nil {
+		t.Fatalf("MovePiece returned err %v", err)
+	}
+	check("after double step")
+	if err := b.MovePiece(b.GetPieceAtPosition(mustPosition(t, "e5")), mustPosition(t, "d6")); err != nil {
+		t.Fatalf("MovePiece returned err %v", err)
+	}
+	check("after en passant")
+	if _, err := b.Undo(); err != nil {
+		t.Fatalf("Undo returned err %v", err)
+	}
+	check("after Undo")
+	if _, err := b.RemovePiece(mustPosition(t, "a1")); err != nil {
+		t.Fatalf("RemovePiece returned err %v", err)
+	}
+	check("after RemovePiece")
+}
+
+func BenchmarkBishopIsLegalMove(b *testing.B) {
+	board := NewBoard()
+	bishop := NewBishop(WHITE)
+	if err := board.PlacePiece(bishop, "c3"); err != nil {
+		b.Fatalf("PlacePiece returned err %v", err)
+	}
+	if err := board.PlacePiece(NewRook(BLACK), "h1"); err != nil {
+		b.Fatalf("PlacePiece returned err %v", err)
+	}
+	squares := board.Squares()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bishop.IsLegalMove(squares[i%len(squares)])
+	}
+}
+
+func TestBitboardRejectsOffBoard(t *testing.T) {
+	b := NewBoard()
+	bishop := NewBishop(WHITE)
+	mustPlace(t, b, bishop, "b1")
+	// k1 would be c2 if read as a bit index, which the Bishop reaches.
+	if bishop.IsLegalMove(mustPosition(t, "k1")) {
+		t.Errorf("IsLegalMove(k1) = true for %v, wanted false", bishop)
+	}
+}
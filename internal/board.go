@@ -8,50 +8,443 @@ import (
 // pieces on it.  It coordinates interactions between pieces and the player.
 type Board struct {
 	positions map[Position]ChessPiece
+
+	// topology decides which edges of the board wrap around.
+	topology Topology
+
+	// width and height are the number of files and ranks on the board.
+	width, height int
+
+	// bits shifts bitboards on boards of up to 64 squares, and is nil on
+	// larger boards.  When set, occupied and byColor are kept in sync with
+	// positions for fast occupancy and attack queries.
+	bits     *bitGeometry
+	occupied bitboard
+	byColor  [3]bitboard
+
+	boardState
+
+	// undo holds each move made, most recent last, so it can be taken back.
+	// redo holds the moves taken back, most recently undone last.  Setting
+	// up the board clears both.
+	undo, redo []moveRecord
 }
 
+// boardState is the part of a Board's state that is not piece placement.
+// It is saved by each move so that the move can be taken back.
+type boardState struct {
+	// sideToMove is the Color expected to move next.
+	sideToMove Color
+
+	// castling holds the FEN castling availability field, such as "KQkq"
+	// or "-".  It is recorded for FEN round trips only.
+	castling string
+
+	// enPassant is the square a Pawn skipped over with a double step on the
+	// previous move, or nil if the previous move was not a double step.
+	enPassant *Position
+
+	// halfmoveClock counts moves since the last capture or Pawn move.
+	halfmoveClock int
+
+	// fullmoveNumber starts at 1 and increments after each Black move.
+	fullmoveNumber int
+}
+
+// MaxWidth is the widest board supported, as files are lettered a-z.
+const MaxWidth = 26
+
+// NewBoard builds an empty 8x8 board that wraps around at every edge.
 func NewBoard() *Board {
+	return NewBoardWithTopology(TORUS)
+}
+
+// NewBoardWithTopology builds an empty 8x8 board whose edges connect
+// according to t.
+func NewBoardWithTopology(t Topology) *Board {
 	return &Board{
 		positions: make(map[Position]ChessPiece),
+		topology:  t,
+		width:     8,
+		height:    8,
+		bits:      newBitGeometry(8, 8, t),
+		boardState: boardState{
+			sideToMove:     WHITE,
+			castling:       "-",
+			fullmoveNumber: 1,
+		},
+	}
+}
+
+// NewSizedBoard builds an empty board width files wide and height ranks
+// tall whose edges connect according to t.  Returns an error if either
+// dimension is less than one or width exceeds MaxWidth.
+func NewSizedBoard(width, height int, t Topology) (*Board, error) {
+	if width < 1 || width > MaxWidth {
+		return nil, fmt.Errorf("NewSizedBoard: width should be 1-%v, got %v", MaxWidth, width)
 	}
+	if height < 1 {
+		return nil, fmt.Errorf("NewSizedBoard: height should be at least 1, got %v", height)
+	}
+	b := NewBoardWithTopology(t)
+	b.width = width
+	b.height = height
+	b.bits = newBitGeometry(width, height, t)
+	return b, nil
+}
+
+// Width returns the number of files on this board.
+func (b *Board) Width() int {
+	return b.width
+}
+
+// Height returns the number of ranks on this board.
+func (b *Board) Height() int {
+	return b.height
+}
+
+// Squares returns every Position on this board, ordered by rank and then
+// file.
+func (b *Board) Squares() []Position {
+	out := make([]Position, 0, b.width*b.height)
+	for rank := 0; rank < b.height; rank++ {
+		for file := 0; file < b.width; file++ {
+			out = append(out, Position{rank: rank, file: file})
+		}
+	}
+	return out
+}
+
+// SideToMove returns the Color expected to move next.  It starts as WHITE
+// and flips after every MovePiece.
+func (b *Board) SideToMove() Color {
+	return b.sideToMove
+}
+
+// SetSideToMove sets the Color expected to move next.  Like any change to
+// the setup, this clears the moves that could be undone or redone.
+func (b *Board) SetSideToMove(c Color) {
+	b.sideToMove = c
+	b.clearHistory()
+}
+
+// Contains returns true if p is a square on this board.
+func (b *Board) Contains(p Position) bool {
+	return p.file >= 0 && p.file < b.width && p.rank >= 0 && p.rank < b.height
+}
+
+// Topology returns how the edges of this board connect.
+func (b *Board) Topology() Topology {
+	return b.topology
+}
+
+// Step computes the position f files and r ranks away from p, wrapping
+// around edges as this board's Topology allows.  Returns false if the step
+// would leave the board.
+func (b *Board) Step(p Position, f, r int) (Position, bool) {
+	return b.topology.step(p, f, r, b.width, b.height)
 }
 
 // PlacePiece places a piece on the board at a particular
-// position.  Returns an error if the position was already occupied or pos is
-// not valid chess notation.
+// position.  Returns an error if the position was already occupied, is off
+// the board, or pos is not valid chess notation.  Like any change to the
+// setup, this clears the moves that could be undone or redone.
 func (b *Board) PlacePiece(piece ChessPiece, pos string) error {
 	p, err := NewPosition(pos)
 	if err != nil {
 		return err
 	}
+	if !b.Contains(*p) {
+		return fmt.Errorf("PlacePiece: %v is off the %vx%v board", p, b.width, b.height)
+	}
 	if current := b.positions[*p]; current != nil {
 		return fmt.Errorf("PlacePiece: cannot place %v on top of %v", piece, current)
 	}
-	b.positions[*p] = piece
+	b.setSquare(*p, piece)
 	piece.place(b, *p)
+	b.clearHistory()
 	return nil
 }
 
-// Moves a piece from one position on the board to another.
+// RemovePiece takes the piece at pos off the board and returns it.  Returns
+// an error if there is no piece there.  Like any change to the setup, this
+// clears the moves that could be undone or redone.
+func (b *Board) RemovePiece(pos Position) (ChessPiece, error) {
+	piece := b.positions[pos]
+	if piece == nil {
+		return nil, fmt.Errorf("RemovePiece: there is no piece on %v", pos)
+	}
+	b.clearSquare(pos)
+	piece.remove()
+	b.clearHistory()
+	return piece, nil
+}
+
+// Moves a piece from one position on the board to another.  This also
+// advances the side to move, move counters and en passant square.  A move
+// that would leave the mover's own King in check is rejected.  The move can
+// be taken back with Undo.
 func (b *Board) MovePiece(piece ChessPiece, pos Position) error {
+	_, err := b.movePiece(piece, pos)
+	return err
+}
+
+// moveRecord is a Move plus what is needed to take it back.
+type moveRecord struct {
+	Move
+
+	// capturedAt is where Move.Captured stood.  This differs from Move.To
+	// for en passant.
+	capturedAt Position
+
+	// prev is the board's state before the move.
+	prev boardState
+}
+
+// movePiece implements MovePiece, returning a record of what happened.
+func (b *Board) movePiece(piece ChessPiece, pos Position) (moveRecord, error) {
+	r, err := b.makeMove(piece, pos)
+	if err != nil {
+		return r, err
+	}
+	if b.InCheck(piece.GetColor()) {
+		b.unmakeMove(r)
+		return moveRecord{}, fmt.Errorf("MovePiece: %v cannot move to %v, leaving its King in check", piece, pos)
+	}
+	b.record(r)
+	return r, nil
+}
+
+// pass gives up the turn of the side to move, recording it so it can be
+// undone like a move.
+func (b *Board) pass() {
+	b.record(b.makePass())
+}
+
+// makePass gives up the turn of the side to move, returning a record of the
+// pass with a nil Piece.
+func (b *Board) makePass() moveRecord {
+	r := moveRecord{prev: b.boardState}
+	b.enPassant = nil
+	b.halfmoveClock++
+	b.passTurn(b.sideToMove)
+	return r
+}
+
+// record adds r to the moves that can be undone.  A new move means the
+// moves undone before it can no longer be redone.
+func (b *Board) record(r moveRecord) {
+	b.undo = append(b.undo, r)
+	b.redo = b.redo[:0]
+}
+
+// clearHistory forgets every move that could be undone or redone.
+func (b *Board) clearHistory() {
+	b.undo = b.undo[:0]
+	b.redo = b.redo[:0]
+}
+
+// LastMove returns the move or pass Undo would take back, if there is one.
+// Changing the setup forgets it, as for Undo.
+func (b *Board) LastMove() (Move, bool) {
+	if len(b.undo) == 0 {
+		return Move{}, false
+	}
+	return b.undo[len(b.undo)-1].Move, true
+}
+
+// Undo takes back the most recent move, restoring the moved piece, any piece
+// it captured and the side to move, counters and en passant square.  Returns
+// the move taken back, which is a pass if its Piece is nil.
+func (b *Board) Undo() (Move, error) {
+	if len(b.undo) == 0 {
+		return Move{}, fmt.Errorf("Undo: no move to take back")
+	}
+	r := b.undo[len(b.undo)-1]
+	b.undo = b.undo[:len(b.undo)-1]
+	b.unmakeMove(r)
+	b.redo = append(b.redo, r)
+	return r.Move, nil
+}
+
+// Redo makes the move most recently taken back by Undo again.  Returns the
+// move made.
+func (b *Board) Redo() (Move, error) {
+	if len(b.redo) == 0 {
+		return Move{}, fmt.Errorf("Redo: no move to make again")
+	}
+	r := b.redo[len(b.redo)-1]
+	b.redo = b.redo[:len(b.redo)-1]
+	if r.Piece == nil {
+		b.makePass()
+	} else if _, err := b.makeMove(r.Piece, r.To); err != nil {
+		// Undo left the board exactly as it was before the move.
+		panic(fmt.Sprintf("Redo: %v", err))
+	}
+	b.undo = append(b.undo, r)
+	return r.Move, nil
+}
+
+// captureOf returns the piece that moving piece to pos would take, or nil if
+// the move captures nothing, along with the square it stands on.  That is pos
+// except for en passant.
+func (b *Board) captureOf(piece ChessPiece, pos Position) (ChessPiece, Position) {
+	destPiece := b.GetPieceAtPosition(pos)
+	if destPiece == piece {
+		// Staying put, as a Rook rolling 8 on a torus does, is not a capture.
+		return nil, pos
+	}
+	pawn, isPawn := piece.(*Pawn)
+	if isPawn && destPiece == nil && pos.file != pawn.position.file {
+		// A diagonal Pawn move onto an empty square is an en passant capture
+		// of the Pawn that just skipped past it.
+		capturedAt, _ := b.Step(pos, 0, -pawn.forward())
+		return b.GetPieceAtPosition(capturedAt), capturedAt
+	}
+	return destPiece, pos
+}
+
+// makeMove moves piece to pos if the piece's geometry allows it, without
+// considering check.
+func (b *Board) makeMove(piece ChessPiece, pos Position) (moveRecord, error) {
 	current := piece.GetPosition()
 	if current == nil {
-		return fmt.Errorf("MovePiece: %v is not on the board", piece)
+		return moveRecord{}, fmt.Errorf("MovePiece: %v is not on the board", piece)
+	}
+	if !b.Contains(pos) {
+		return moveRecord{}, fmt.Errorf("MovePiece: %v is off the %vx%v board", pos, b.width, b.height)
 	}
 	if !piece.IsLegalMove(pos) {
-		return fmt.Errorf("MovePiece: %v cannot move to %v", piece, pos)
+		return moveRecord{}, fmt.Errorf("MovePiece: %v cannot move to %v", piece, pos)
 	}
-	destPiece := b.GetPieceAtPosition(pos)
+	from := *current
+	prev := b.boardState
+	destPiece, capturedAt := b.captureOf(piece, pos)
+	pawn, isPawn := piece.(*Pawn)
 	if destPiece != nil {
 		destPiece.remove()
+		b.clearSquare(capturedAt)
 	}
-	delete(b.positions, *current)
-	b.positions[pos] = piece
+	b.clearSquare(from)
+	b.setSquare(pos, piece)
 	piece.place(b, pos)
-	return nil
+
+	b.enPassant = nil
+	if isPawn {
+		if oneStep, _ := b.Step(from, 0, pawn.forward()); pos.file == from.file && pos != oneStep {
+			b.enPassant = &oneStep
+		}
+	}
+	if isPawn || destPiece != nil {
+		b.halfmoveClock = 0
+	} else {
+		b.halfmoveClock++
+	}
+	b.passTurn(piece.GetColor())
+	return moveRecord{
+		Move: Move{
+			Piece:    piece,
+			From:     from,
+			To:       pos,
+			Captured: destPiece,
+		},
+		capturedAt: capturedAt,
+		prev:       prev,
+	}, nil
+}
+
+// unmakeMove takes back a move made by makeMove, or a pass, restoring both
+// the board and the pieces involved.
+func (b *Board) unmakeMove(r moveRecord) {
+	if r.Piece == nil {
+		b.boardState = r.prev
+		return
+	}
+	b.clearSquare(r.To)
+	b.setSquare(r.From, r.Piece)
+	r.Piece.place(b, r.From)
+	if r.Captured != nil {
+		b.setSquare(r.capturedAt, r.Captured)
+		r.Captured.place(b, r.capturedAt)
+	}
+	b.boardState = r.prev
+}
+
+// passTurn hands the move to the opponent of c, advancing the full move
+// number after Black.
+func (b *Board) passTurn(c Color) {
+	if c == BLACK {
+		b.fullmoveNumber++
+	}
+	b.sideToMove = opponent(c)
+}
+
+// setSquare puts piece on the empty square pos, keeping the bitboards in
+// sync with the positions map.
+func (b *Board) setSquare(pos Position, piece ChessPiece) {
+	b.positions[pos] = piece
+	if b.bits != nil {
+		bit := b.bits.bit(pos)
+		b.occupied |= bit
+		b.byColor[piece.GetColor()] |= bit
+	}
+}
+
+// clearSquare empties pos, keeping the bitboards in sync with the positions
+// map.
+func (b *Board) clearSquare(pos Position) {
+	piece := b.positions[pos]
+	if piece == nil {
+		return
+	}
+	delete(b.positions, pos)
+	if b.bits != nil {
+		bit := b.bits.bit(pos)
+		b.occupied &^= bit
+		b.byColor[piece.GetColor()] &^= bit
+	}
 }
 
 // Gets the piece at a given position, or nil if the space is empty.
 func (b *Board) GetPieceAtPosition(pos Position) ChessPiece {
 	return b.positions[pos]
 }
+
+// Pieces returns every piece of Color c on the board, ordered by position.
+func (b *Board) Pieces(c Color) []ChessPiece {
+	positions := make([]Position, 0)
+	for p, piece := range b.positions {
+		if piece.GetColor() == c {
+			positions = append(positions, p)
+		}
+	}
+	sortPositions(positions)
+	out := make([]ChessPiece, 0, len(positions))
+	for _, p := range positions {
+		out = append(out, b.positions[p])
+	}
+	return out
+}
+
+// AllLegalMoves returns every legal move available to pieces of Color c,
+// grouped by piece in position order.  Moves that would leave a King of
+// Color c in check are excluded.
+func (b *Board) AllLegalMoves(c Color) []Move {
+	out := make([]Move, 0)
+	for _, piece := range b.Pieces(c) {
+		from := *piece.GetPosition()
+		for _, to := range piece.LegalMoves() {
+			if b.LeavesKingInCheck(piece, to) {
+				continue
+			}
+			captured, _ := b.captureOf(piece, to)
+			out = append(out, Move{
+				Piece:    piece,
+				From:     from,
+				To:       to,
+				Captured: captured,
+			})
+		}
+	}
+	return out
+}
@@ -42,6 +42,32 @@ func TestMovePieceStationary(t *testing.T) {
 	assertPieceConsistent(t, b, src, mustPosition(t, "d3"))
 }
 
+func TestMovePieceStationaryIsNotCapture(t *testing.T) {
+	b := NewBoard()
+	rook := NewRook(BLACK)
+	mustPlace(t, b, rook, "h1")
+	b.SetSideToMove(BLACK)
+	g := NewGame(b)
+
+	// Rolling 8 on the torus brings the Rook back around to h1.
+	dest, _ := b.Step(mustPosition(t, "h1"), 8, 0)
+	if err := g.Move(rook, dest); err != nil {
+		t.Fatalf("Move returned err %v, wanted nil", err)
+	}
+
+	assertPieceConsistent(t, b, rook, mustPosition(t, "h1"))
+	if m := g.History()[0]; m.Captured != nil || m.String() != "h1-h1" {
+		t.Errorf("History()[0] = %v capturing %v, wanted h1-h1 capturing nothing", m, m.Captured)
+	}
+	if fen, want := b.ToFEN(), "8/8/8/8/8/8/8/7r w - - 1 2"; fen != want {
+		t.Errorf("ToFEN() = %q, wanted %q with the halfmove clock counting on", fen, want)
+	}
+	if _, err := b.Undo(); err != nil {
+		t.Fatalf("Undo returned err %v, wanted nil", err)
+	}
+	assertPieceConsistent(t, b, rook, mustPosition(t, "h1"))
+}
+
 func TestMovePieceCapturing(t *testing.T) {
 	b := NewBoard()
 	src := NewRook(WHITE)
@@ -96,6 +122,26 @@ func TestMovePieceNotOnBoard(t *testing.T) {
 	}
 }
 
+func TestMovePieceOffBoard(t *testing.T) {
+	b := NewBoard()
+	queen := NewQueen(WHITE)
+	mustPlace(t, b, queen, "a1")
+
+	// i1 is one file past h1, and must not be taken for a square on rank 2.
+	err := b.MovePiece(queen, mustPosition(t, "i1"))
+
+	want := errors.New("MovePiece: i1 is off the 8x8 board")
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("MovePiece returned err %v, wanted %v", err, want)
+	}
+	if pos := queen.GetPosition(); *pos != mustPosition(t, "a1") {
+		t.Errorf("Queen should have stayed on a1, was on %v", pos)
+	}
+	if b.IsAttacked(mustPosition(t, "a2"), BLACK) || b.GetPieceAtPosition(mustPosition(t, "a2")) != nil {
+		t.Errorf("a2 should still be empty after a failed move to i1")
+	}
+}
+
 func TestMovePieceOnTop(t *testing.T) {
 	b := NewBoard()
 	src := NewRook(WHITE)
@@ -114,3 +160,254 @@ func TestMovePieceOnTop(t *testing.T) {
 		t.Errorf("Failing to place piece should have nil position, was %v", pos)
 	}
 }
+
+var sizedBoardTestCases = []struct {
+	width, height int
+	pos           string
+	wantError     error
+}{
+	{5, 5, "e5", nil},
+	{5, 5, "f1", errors.New("PlacePiece: f1 is off the 5x5 board")},
+	{5, 5, "a6", errors.New("PlacePiece: a6 is off the 5x5 board")},
+	{10, 8, "j8", nil},
+	{10, 8, "j9", errors.New("PlacePiece: j9 is off the 10x8 board")},
+	{16, 16, "p16", nil},
+	{16, 16, "k12", nil},
+	{26, 30, "z30", nil},
+}
+
+func TestPlacePieceSizedBoard(t *testing.T) {
+	for _, tc := range sizedBoardTestCases {
+		b, err := NewSizedBoard(tc.width, tc.height, TORUS)
+		if err != nil {
+			t.Fatalf("NewSizedBoard(%v,%v) returned err %v", tc.width, tc.height, err)
+		}
+		err = b.PlacePiece(NewRook(WHITE), tc.pos)
+		if !reflect.DeepEqual(err, tc.wantError) {
+			t.Errorf("%vx%v PlacePiece(%v) returned err %v, wanted %v", tc.width, tc.height, tc.pos, err, tc.wantError)
+		}
+	}
+}
+
+func TestNewSizedBoardInvalid(t *testing.T) {
+	for _, dims := range [][2]int{{0, 8}, {8, 0}, {27, 8}, {-1, -1}} {
+		if _, err := NewSizedBoard(dims[0], dims[1], TORUS); err == nil {
+			t.Errorf("NewSizedBoard(%v,%v) returned nil err", dims[0], dims[1])
+		}
+	}
+}
+
+var sizedMovementTestCases = []struct {
+	width, height  int
+	topology       Topology
+	newPiece       func(c Color) ChessPiece
+	start          string
+	dest           string
+	blackPositions []string
+	want           bool
+}{
+	{5, 5, TORUS, rookPiece, "a1", "e1", []string{}, true},
+	{5, 5, TORUS, rookPiece, "a1", "e1", []string{"c1"}, true},
+	{5, 5, TORUS, rookPiece, "a1", "f1", []string{}, false},
+	{5, 5, STANDARD, rookPiece, "a1", "e1", []string{"c1"}, false},
+	{5, 5, TORUS, bishopPiece, "a1", "e5", []string{}, true},
+	{5, 5, TORUS, bishopPiece, "b1", "a5", []string{}, true}, // Wraparound down left
+	{5, 5, STANDARD, bishopPiece, "b1", "a5", []string{}, false},
+	{5, 5, TORUS, knightPiece, "a1", "e3", []string{}, true},
+	{5, 5, TORUS, kingPiece, "a1", "e5", []string{}, true},
+	{5, 5, STANDARD, pawnPiece, "c2", "c4", []string{}, true},
+	{10, 8, TORUS, rookPiece, "a1", "j1", []string{}, true},
+	{10, 8, TORUS, queenPiece, "a1", "h8", []string{}, true},
+	{10, 8, TORUS, queenPiece, "a1", "j8", []string{}, true},
+	{10, 8, STANDARD, queenPiece, "a1", "j8", []string{}, false},
+	{16, 16, STANDARD, queenPiece, "a1", "p16", []string{}, true},
+	{16, 16, STANDARD, queenPiece, "a1", "p16", []string{"k11"}, false},
+	{16, 16, STANDARD, rookPiece, "k12", "k1", []string{}, true},
+}
+
+func TestSizedBoardMovement(t *testing.T) {
+	for _, tc := range sizedMovementTestCases {
+		b, err := NewSizedBoard(tc.width, tc.height, tc.topology)
+		if err != nil {
+			t.Fatalf("NewSizedBoard(%v,%v) returned err %v", tc.width, tc.height, err)
+		}
+		p := tc.newPiece(WHITE)
+		mustPlace(t, b, p, tc.start)
+
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewRook(BLACK), pos)
+		}
+		got := p.IsLegalMove(mustPosition(t, tc.dest))
+		if got != tc.want {
+			t.Errorf("%vx%v %v %v to %v with black %v = %v, wanted %v", tc.width, tc.height, tc.topology, p, tc.dest, tc.blackPositions, got, tc.want)
+		}
+	}
+}
+
+func TestAllLegalMoves(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	rook := NewRook(WHITE)
+	mustPlace(t, b, rook, "a1")
+	knight := NewKnight(WHITE)
+	mustPlace(t, b, knight, "b1")
+	mustPlace(t, b, NewPawn(WHITE), "a3")
+	target := NewBishop(BLACK)
+	mustPlace(t, b, target, "a2")
+	mustPlace(t, b, NewKing(BLACK), "h8")
+
+	got := b.AllLegalMoves(WHITE)
+
+	want := []Move{
+		{Piece: rook, From: mustPosition(t, "a1"), To: mustPosition(t, "a2"), Captured: target},
+		{Piece: knight, From: mustPosition(t, "b1"), To: mustPosition(t, "d2")},
+		{Piece: knight, From: mustPosition(t, "b1"), To: mustPosition(t, "c3")},
+	}
+	// The Pawn on a3 is not on its starting rank and a4 is empty.
+	pawn := b.GetPieceAtPosition(mustPosition(t, "a3"))
+	want = append(want, Move{Piece: pawn, From: mustPosition(t, "a3"), To: mustPosition(t, "a4")})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllLegalMoves(WHITE) = %v, wanted %v", got, want)
+	}
+	// The Bishop on a2 has seven moves and the cornered King three.
+	if got := b.AllLegalMoves(BLACK); len(got) != 10 {
+		t.Errorf("AllLegalMoves(BLACK) returned %v moves, wanted 10: %v", len(got), got)
+	}
+}
+
+func TestAllLegalMovesEnPassant(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 21"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	blackPawn := b.GetPieceAtPosition(mustPosition(t, "d5"))
+
+	found := false
+	for _, m := range b.AllLegalMoves(WHITE) {
+		if m.To != mustPosition(t, "d6") {
+			continue
+		}
+		found = true
+		if m.Captured != blackPawn || m.String() != "e5xd6" {
+			t.Errorf("en passant move = %v capturing %v, wanted e5xd6 capturing %v", m, m.Captured, blackPawn)
+		}
+	}
+	if !found {
+		t.Errorf("AllLegalMoves(WHITE) is missing the en passant capture on d6")
+	}
+}
+
+func TestRemovePiece(t *testing.T) {
+	b := NewBoard()
+	src := NewRook(WHITE)
+	mustPlace(t, b, src, "d3")
+
+	got, err := b.RemovePiece(mustPosition(t, "d3"))
+	if err != nil || got != src {
+		t.Errorf("RemovePiece(d3) = %v, %v, wanted %v", got, err, src)
+	}
+	if src.GetPosition() != nil || b.GetPieceAtPosition(mustPosition(t, "d3")) != nil {
+		t.Errorf("RemovePiece left %v on the board", src)
+	}
+	if _, err := b.RemovePiece(mustPosition(t, "d3")); err == nil {
+		t.Errorf("RemovePiece on an empty square returned no error")
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4k3/3p4/8/4P3/8/8/8/R3K3 b - - 3 7"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	start := b.ToFEN()
+	blackPawn := b.GetPieceAtPosition(mustPosition(t, "d7"))
+	whitePawn := b.GetPieceAtPosition(mustPosition(t, "e5"))
+	rook := b.GetPieceAtPosition(mustPosition(t, "a1"))
+
+	// A double step and then an en passant capture.
+	if err := b.MovePiece(blackPawn, mustPosition(t, "d5")); err != nil {
+		t.Fatalf("MovePiece(d5) returned err %v", err)
+	}
+	afterDouble := b.ToFEN()
+	if err := b.MovePiece(whitePawn, mustPosition(t, "d6")); err != nil {
+		t.Fatalf("MovePiece(exd6) returned err %v", err)
+	}
+	afterCapture := b.ToFEN()
+	if blackPawn.GetPosition() != nil {
+		t.Fatalf("en passant left the Black Pawn on %v", blackPawn.GetPosition())
+	}
+
+	m, err := b.Undo()
+	if err != nil || m.Piece != whitePawn || m.Captured != blackPawn {
+		t.Errorf("Undo() = %v, %v, wanted the en passant capture", m, err)
+	}
+	if got := b.ToFEN(); got != afterDouble {
+		t.Errorf("Undo() left FEN %v, wanted %v", got, afterDouble)
+	}
+	assertPieceConsistent(t, b, blackPawn, mustPosition(t, "d5"))
+	assertPieceConsistent(t, b, whitePawn, mustPosition(t, "e5"))
+
+	if _, err := b.Undo(); err != nil {
+		t.Errorf("Undo() returned err %v", err)
+	}
+	if got := b.ToFEN(); got != start {
+		t.Errorf("Undo() twice left FEN %v, wanted %v", got, start)
+	}
+	if _, err := b.Undo(); err == nil {
+		t.Errorf("Undo() with nothing to undo returned no error")
+	}
+
+	for _, want := range []string{afterDouble, afterCapture} {
+		if _, err := b.Redo(); err != nil {
+			t.Fatalf("Redo() returned err %v", err)
+		}
+		if got := b.ToFEN(); got != want {
+			t.Errorf("Redo() left FEN %v, wanted %v", got, want)
+		}
+	}
+	if _, err := b.Redo(); err == nil {
+		t.Errorf("Redo() with nothing to redo returned no error")
+	}
+
+	// A new move forgets what was undone.
+	if _, err := b.Undo(); err != nil {
+		t.Fatalf("Undo() returned err %v", err)
+	}
+	if err := b.MovePiece(rook, mustPosition(t, "a4")); err != nil {
+		t.Fatalf("MovePiece(Ra4) returned err %v", err)
+	}
+	if _, err := b.Redo(); err == nil {
+		t.Errorf("Redo() after a new move returned no error")
+	}
+
+	// Setting up the board forgets every move.
+	mustPlace(t, b, NewKnight(WHITE), "h1")
+	if _, err := b.Undo(); err == nil {
+		t.Errorf("Undo() after PlacePiece returned no error")
+	}
+}
+
+func TestUndoRedoPass(t *testing.T) {
+	b := NewBoard()
+	rook := NewRook(WHITE)
+	mustPlace(t, b, rook, "a1")
+	g := NewGame(b)
+	if err := g.Move(rook, mustPosition(t, "a3")); err != nil {
+		t.Fatalf("Move returned err %v", err)
+	}
+	g.Pass()
+	before := b.ToFEN()
+
+	m, err := g.Undo()
+	if err != nil || m.Piece != nil {
+		t.Errorf("Undo() = %v, %v, wanted the pass", m, err)
+	}
+	if b.SideToMove() != BLACK || len(g.History()) != 1 {
+		t.Errorf("Undo() of a pass left %v to move and history %v", b.SideToMove(), g.History())
+	}
+	if _, err := g.Redo(); err != nil {
+		t.Fatalf("Redo() returned err %v", err)
+	}
+	if got := b.ToFEN(); got != before || len(g.History()) != 2 {
+		t.Errorf("Redo() of a pass left FEN %v and history %v, wanted %v", got, g.History(), before)
+	}
+}
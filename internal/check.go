@@ -0,0 +1,35 @@
+package internal
+
+// InCheck returns true if any King of Color c could be captured by one of
+// its opponent's pieces.  Attacks across wrapped edges count.
+func (b *Board) InCheck(c Color) bool {
+	for kingPos, piece := range b.positions {
+		if _, ok := piece.(*King); ok && piece.GetColor() == c && b.IsAttacked(kingPos, opponent(c)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LeavesKingInCheck returns true if moving piece to dest would leave a King
+// of the piece's own Color in check.  Returns false if the move is not
+// allowed by the piece's geometry at all.
+func (b *Board) LeavesKingInCheck(piece ChessPiece, dest Position) bool {
+	r, err := b.makeMove(piece, dest)
+	if err != nil {
+		return false
+	}
+	defer b.unmakeMove(r)
+	return b.InCheck(piece.GetColor())
+}
+
+// IsCheckmate returns true if Color c is in check and has no legal move
+// to escape it.
+func (b *Board) IsCheckmate(c Color) bool {
+	return b.InCheck(c) && len(b.AllLegalMoves(c)) == 0
+}
+
+// IsStalemate returns true if Color c is not in check but has no legal move.
+func (b *Board) IsStalemate(c Color) bool {
+	return !b.InCheck(c) && len(b.AllLegalMoves(c)) == 0
+}
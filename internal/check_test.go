@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var checkTestCases = []struct {
+	topology      Topology
+	fen           string
+	color         Color
+	wantCheck     bool
+	wantCheckmate bool
+	wantStalemate bool
+}{
+	{STANDARD, "4r2k/8/8/8/8/8/8/4K3 w", WHITE, true, false, false},
+	{STANDARD, "4r2k/8/8/8/8/8/4P3/4K3 w", WHITE, false, false, false},
+	{STANDARD, "8/8/8/8/8/8/8/KR5r w", WHITE, false, false, false},
+	{TORUS, "8/8/8/8/8/8/8/KR5r w", WHITE, true, false, false}, // Attacked through the edge
+	{HORIZONTAL_CYLINDER, "8/8/8/8/8/8/8/KR5r w", WHITE, true, false, false},
+	{VERTICAL_CYLINDER, "8/8/8/8/8/8/8/KR5r w", WHITE, false, false, false},
+	{STANDARD, "R5k1/5ppp/8/8/8/8/8/6K1 b", BLACK, true, true, false},
+	{STANDARD, "R5k1/5pp1/8/8/8/8/8/6K1 b", BLACK, true, false, false}, // h7 is free
+	{STANDARD, "7k/5Q2/6K1/8/8/8/8/8 b", BLACK, false, false, true},
+	{TORUS, "7k/5Q2/6K1/8/8/8/8/8 b", BLACK, false, false, false}, // Escapes over the edge
+	{STANDARD, "8/8/8/8/8/8/8/8 w", WHITE, false, false, true},
+}
+
+func TestCheckDetection(t *testing.T) {
+	for _, tc := range checkTestCases {
+		b := NewBoardWithTopology(tc.topology)
+		if err := b.FromFEN(tc.fen); err != nil {
+			t.Fatalf("FromFEN(%v) returned err %v", tc.fen, err)
+		}
+		if got := b.InCheck(tc.color); got != tc.wantCheck {
+			t.Errorf("%v %v InCheck(%v) = %v, wanted %v", tc.topology, tc.fen, tc.color, got, tc.wantCheck)
+		}
+		if got := b.IsCheckmate(tc.color); got != tc.wantCheckmate {
+			t.Errorf("%v %v IsCheckmate(%v) = %v, wanted %v", tc.topology, tc.fen, tc.color, got, tc.wantCheckmate)
+		}
+		if got := b.IsStalemate(tc.color); got != tc.wantStalemate {
+			t.Errorf("%v %v IsStalemate(%v) = %v, wanted %v", tc.topology, tc.fen, tc.color, got, tc.wantStalemate)
+		}
+		// Queries must leave the board as they found it.
+		if got := b.ToFEN(); got[:len(tc.fen)] != tc.fen {
+			t.Errorf("%v ToFEN() after queries = %v, wanted prefix %v", tc.topology, got, tc.fen)
+		}
+	}
+}
+
+func TestLeavesKingInCheck(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4r2k/8/8/8/8/8/4B3/4K3 w"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	bishop := b.GetPieceAtPosition(mustPosition(t, "e2"))
+	king := b.GetPieceAtPosition(mustPosition(t, "e1"))
+
+	if !b.LeavesKingInCheck(bishop, mustPosition(t, "d3")) {
+		t.Errorf("Pinned %v moving to d3 should leave its King in check", bishop)
+	}
+	if b.LeavesKingInCheck(king, mustPosition(t, "d1")) {
+		t.Errorf("%v moving to d1 should not be in check", king)
+	}
+
+	err := b.MovePiece(bishop, mustPosition(t, "d3"))
+
+	want := errors.New("MovePiece: White Bishop at e2 cannot move to d3, leaving its King in check")
+	// Generally undesirable, but want to verify error strings.
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("MovePiece returned err %v, wanted %v", err, want)
+	}
+	assertPieceConsistent(t, b, bishop, mustPosition(t, "e2"))
+	if piece := b.GetPieceAtPosition(mustPosition(t, "d3")); piece != nil {
+		t.Errorf("GetPieceAtPosition after failed move should be nil, was %v", piece)
+	}
+	if got := len(b.AllLegalMoves(WHITE)); got != 4 {
+		// The King has d1, d2, f1 and f2 while the pinned Bishop has nothing.
+		t.Errorf("AllLegalMoves(WHITE) returned %v moves, wanted 4: %v", got, b.AllLegalMoves(WHITE))
+	}
+}
+
+func TestMovePieceRestoresCapture(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4r2k/8/8/7n/8/8/4Q3/4K3 w"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	queen := b.GetPieceAtPosition(mustPosition(t, "e2"))
+	knight := b.GetPieceAtPosition(mustPosition(t, "h5"))
+
+	if err := b.MovePiece(queen, mustPosition(t, "h5")); err == nil {
+		t.Errorf("MovePiece exposing the King returned nil err")
+	}
+
+	assertPieceConsistent(t, b, queen, mustPosition(t, "e2"))
+	assertPieceConsistent(t, b, knight, mustPosition(t, "h5"))
+	if got, want := b.ToFEN(), "4r2k/8/8/7n/8/8/4Q3/4K3 w - - 0 1"; got != want {
+		t.Errorf("ToFEN() after rejected capture = %v, wanted %v", got, want)
+	}
+}
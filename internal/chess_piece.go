@@ -13,6 +13,26 @@ const (
 	BLACK
 )
 
+func (c Color) String() string {
+	switch c {
+	case WHITE:
+		return "White"
+	case BLACK:
+		return "Black"
+	case EMPTY:
+		return "Empty"
+	}
+	return "Unknown"
+}
+
+// opponent returns the Color playing against c.
+func opponent(c Color) Color {
+	if c == WHITE {
+		return BLACK
+	}
+	return WHITE
+}
+
 // ChessPiece is the interface implemented by each piece allowing them
 // to be moved around the board legally.
 // Unexported functions are used in partnership with Board.
@@ -24,6 +44,10 @@ type ChessPiece interface {
 	// and false otherwise.
 	IsLegalMove(dest Position) bool
 
+	// LegalMoves returns every Position this piece can legally move to,
+	// ordered by rank and then file.  Staying put is not included.
+	LegalMoves() []Position
+
 	// GetPosition returns the current position of this piece on the
 	// board.  Returns nil if not on the board.
 	GetPosition() *Position
@@ -31,6 +55,9 @@ type ChessPiece interface {
 	// GetColor returns the Color of this piece.
 	GetColor() Color
 
+	// GetName returns the name of this kind of piece, such as "Rook".
+	GetName() string
+
 	// place puts this ChessPiece on the given Board at Position p
 	place(b *Board, p Position)
 
@@ -70,6 +97,71 @@ func NewBishop(c Color) *Bishop {
 	}
 }
 
+// Knight is a normal chess Knight.
+type Knight struct {
+	basicPiece
+}
+
+// NewKnight builds a new Knight off-board.
+// It can be added to the board by PlacePiece.
+func NewKnight(c Color) *Knight {
+	return &Knight{
+		basicPiece{
+			name:  "Knight",
+			color: c,
+		},
+	}
+}
+
+// Queen is a normal chess Queen.
+type Queen struct {
+	basicPiece
+}
+
+// NewQueen builds a new Queen off-board.
+// It can be added to the board by PlacePiece.
+func NewQueen(c Color) *Queen {
+	return &Queen{
+		basicPiece{
+			name:  "Queen",
+			color: c,
+		},
+	}
+}
+
+// King is a normal chess King.
+type King struct {
+	basicPiece
+}
+
+// NewKing builds a new King off-board.
+// It can be added to the board by PlacePiece.
+func NewKing(c Color) *King {
+	return &King{
+		basicPiece{
+			name:  "King",
+			color: c,
+		},
+	}
+}
+
+// Pawn is a chess Pawn.  White Pawns advance up the board and Black Pawns
+// advance down it, wrapping around if the board's Topology allows.
+type Pawn struct {
+	basicPiece
+}
+
+// NewPawn builds a new Pawn off-board.
+// It can be added to the board by PlacePiece.
+func NewPawn(c Color) *Pawn {
+	return &Pawn{
+		basicPiece{
+			name:  "Pawn",
+			color: c,
+		},
+	}
+}
+
 // basicPiece collects behaviors every piece needs to represent position
 // on the board and do basic movements.
 type basicPiece struct {
@@ -90,6 +182,10 @@ func (bP *basicPiece) GetColor() Color {
 	return bP.color
 }
 
+func (bP *basicPiece) GetName() string {
+	return bP.name
+}
+
 func (bP *basicPiece) place(b *Board, p Position) {
 	bP.board = b
 	bP.position = p
@@ -107,29 +203,116 @@ func (bP *basicPiece) GetPosition() *Position {
 }
 
 func (bP *basicPiece) String() string {
-	color := "Unknown"
-	if bP.color == WHITE {
-		color = "White"
-	} else if bP.color == BLACK {
-		color = "Black"
-	} else if bP.color == EMPTY {
-		color = "Empty"
-	}
 	if bP.board == nil {
-		return fmt.Sprintf("Off board %v %v", color, bP.name)
+		return fmt.Sprintf("Off board %v %v", bP.color, bP.name)
 	}
-	return fmt.Sprintf("%v %v at %v", color, bP.name, bP.position)
+	return fmt.Sprintf("%v %v at %v", bP.color, bP.name, bP.position)
 }
 
-// checkClearSpaces returns true if all Positions in positions are clear
-// on the provided Board b.
-func checkClearSpaces(positions []Position, b *Board) bool {
-	for _, p := range positions {
-		if b.GetPieceAtPosition(p) != nil {
+// checkRay returns true if dest can be reached from this piece's position by
+// repeatedly stepping f files and r ranks without passing through another
+// piece.  The walk stops at an edge that does not wrap, or if it wraps back
+// around to the starting square.
+func (bP *basicPiece) checkRay(dest Position, f, r int) bool {
+	if g := bP.board.bits; g != nil {
+		// An off-board dest would alias the bit of a real square.
+		return bP.board.Contains(dest) && g.ray(bP.position, f, r, bP.board.occupied)&g.bit(dest) != 0
+	}
+	search, ok := bP.board.Step(bP.position, f, r)
+	for ok && search != bP.position {
+		if search == dest {
+			return true
+		}
+		if bP.board.GetPieceAtPosition(search) != nil {
 			return false
 		}
+		search, ok = bP.board.Step(search, f, r)
+	}
+	return false
+}
+
+// checkStep returns true if dest is exactly f files and r ranks away from
+// this piece's position.
+func (bP *basicPiece) checkStep(dest Position, f, r int) bool {
+	p, ok := bP.board.Step(bP.position, f, r)
+	return ok && p == dest
+}
+
+// collectRay adds every square reachable from this piece's position by
+// repeatedly stepping f files and r ranks to moves.  The walk stops after
+// capturing, before a piece of this piece's own color, at an edge that does
+// not wrap, or on wrapping back to the starting square.
+func (bP *basicPiece) collectRay(moves map[Position]bool, f, r int) {
+	search, ok := bP.board.Step(bP.position, f, r)
+	for ok && search != bP.position {
+		destPiece := bP.board.GetPieceAtPosition(search)
+		if destPiece != nil {
+			if destPiece.GetColor() != bP.color {
+				moves[search] = true
+			}
+			return
+		}
+		moves[search] = true
+		search, ok = bP.board.Step(search, f, r)
+	}
+}
+
+// slideMoves returns every square this piece can slide to along directions,
+// as collectRay finds them, ordered by rank and then file.
+func (bP *basicPiece) slideMoves(directions [][2]int) []Position {
+	if g := bP.board.bits; g != nil {
+		return g.positions(g.rays(bP.position, directions, bP.board.occupied) &^ bP.board.byColor[bP.color])
+	}
+	moves := make(map[Position]bool)
+	for _, d := range directions {
+		bP.collectRay(moves, d[0], d[1])
+	}
+	return sortedPositions(moves)
+}
+
+// collectSteps adds each square a single (file, rank) offset away from this
+// piece's position to moves if it is on the board and not blocked by a
+// piece of this piece's own color.
+func (bP *basicPiece) collectSteps(moves map[Position]bool, offsets [][2]int) {
+	for _, o := range offsets {
+		p, ok := bP.board.Step(bP.position, o[0], o[1])
+		if ok && p != bP.position && bP.checkDestination(p) {
+			moves[p] = true
+		}
 	}
-	return true
+}
+
+// sortedPositions flattens a set of Positions into a slice ordered by rank
+// and then file.
+func sortedPositions(moves map[Position]bool) []Position {
+	out := make([]Position, 0, len(moves))
+	for p := range moves {
+		out = append(out, p)
+	}
+	sortPositions(out)
+	return out
+}
+
+// rookDirections are the (file, rank) steps a Rook slides along.
+var rookDirections = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// bishopDirections are the (file, rank) steps a Bishop slides along.
+var bishopDirections = [][2]int{{1, 1}, {-1, 1}, {1, -1}, {-1, -1}}
+
+// kingOffsets are the (file, rank) steps available to a King.
+var kingOffsets = append(append([][2]int{}, rookDirections...), bishopDirections...)
+
+// checkDestination returns true if dest is on the board and not occupied by
+// a piece of this piece's own color.
+func (bP *basicPiece) checkDestination(dest Position) bool {
+	if !bP.board.Contains(dest) {
+		return false
+	}
+	if g := bP.board.bits; g != nil {
+		return bP.board.byColor[bP.color]&g.bit(dest) == 0
+	}
+	destPiece := bP.board.GetPieceAtPosition(dest)
+	return destPiece == nil || destPiece.GetColor() != bP.color
 }
 
 func (r *Rook) IsLegalMove(dest Position) bool {
@@ -145,54 +328,24 @@ func (r *Rook) IsLegalMove(dest Position) bool {
 		// If not in at least the same rank or same file, this is impossible.
 		return false
 	}
-	destPiece := r.board.GetPieceAtPosition(dest)
-	if destPiece != nil && destPiece.GetColor() == r.GetColor() {
-		// This is running into a piece on this Rook's side.
+	if !r.checkDestination(dest) {
+		// This is off the board or running into a piece on this Rook's side.
 		return false
 	}
 	// Now check for collisions in relevant directions.
 	if dest.rank == r.position.rank {
-		rightSpaces := make([]Position, 0)
-		search := r.position.Move(1, 0)
-		for search != dest && search != r.position {
-			rightSpaces = append(rightSpaces, search)
-			search = search.Move(1, 0)
-		}
-		if search == dest && checkClearSpaces(rightSpaces, r.board) {
-			// Clear path to right.
-			return true
-		}
-		leftSpaces := make([]Position, 0)
-		search = r.position.Move(-1, 0)
-		for search != dest && search != r.position {
-			leftSpaces = append(leftSpaces, search)
-			search = search.Move(-1, 0)
-		}
-		// At this point either left is clear or there is no path.
-		return search == dest && checkClearSpaces(leftSpaces, r.board)
-	} else if dest.file == r.position.file {
-		upSpaces := make([]Position, 0)
-		search := r.position.Move(0, 1)
-		for search != dest && search != r.position {
-			upSpaces = append(upSpaces, search)
-			search = search.Move(0, 1)
-		}
-		if search == dest && checkClearSpaces(upSpaces, r.board) {
-			// Clear path up.
-			return true
-		}
-		downSpaces := make([]Position, 0)
-		search = r.position.Move(0, -1)
-		for search != dest && search != r.position {
-			downSpaces = append(downSpaces, search)
-			search = search.Move(0, -1)
-		}
-		// At this point either down is clear or there is no path
-		return search == dest && checkClearSpaces(downSpaces, r.board)
+		// Either right or left must be clear.
+		return r.checkRay(dest, 1, 0) || r.checkRay(dest, -1, 0)
 	}
-	// This should never happen, as it indicates a logic error in this function
-	// The earlier traps ensuring equal rank or file should have headed this off.
-	panic(fmt.Sprintf("error computing move of %v to %v", r, dest))
+	// Either up or down must be clear.
+	return r.checkRay(dest, 0, 1) || r.checkRay(dest, 0, -1)
+}
+
+func (r *Rook) LegalMoves() []Position {
+	if r.board == nil {
+		return []Position{}
+	}
+	return r.slideMoves(rookDirections)
 }
 
 func (b *Bishop) IsLegalMove(dest Position) bool {
@@ -204,47 +357,195 @@ func (b *Bishop) IsLegalMove(dest Position) bool {
 		// Can stay put
 		return true
 	}
-	destPiece := b.board.GetPieceAtPosition(dest)
-	if destPiece != nil && destPiece.GetColor() == b.GetColor() {
-		// This is running into a piece on this piece's side.
+	if !b.checkDestination(dest) {
+		// This is off the board or running into a piece on this piece's side.
 		return false
 	}
-	// Now check for collisions in relevant directions.
-	upRightSpaces := make([]Position, 0)
-	search := b.position.Move(1, 1)
-	for search != dest && search != b.position {
-		upRightSpaces = append(upRightSpaces, search)
-		search = search.Move(1, 1)
-	}
-	if search == dest && checkClearSpaces(upRightSpaces, b.board) {
-		// Clear path to up right.
+	// Now check for collisions in each diagonal direction.
+	return b.checkRay(dest, 1, 1) || b.checkRay(dest, -1, 1) ||
+		b.checkRay(dest, 1, -1) || b.checkRay(dest, -1, -1)
+}
+
+func (b *Bishop) LegalMoves() []Position {
+	if b.board == nil {
+		return []Position{}
+	}
+	return b.slideMoves(bishopDirections)
+}
+
+// knightOffsets are the (file, rank) jumps available to a Knight.
+var knightOffsets = [][2]int{
+	{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2},
+}
+
+func (n *Knight) IsLegalMove(dest Position) bool {
+	if n.board == nil {
+		// Not on the board.
+		return false
+	}
+	if dest == n.position {
+		// Can stay put.
+		return true
+	}
+	if !n.checkDestination(dest) {
+		// This is off the board or running into a piece on this piece's side.
+		return false
+	}
+	// Knights jump, so only the landing square matters.
+	for _, o := range knightOffsets {
+		if n.checkStep(dest, o[0], o[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Knight) LegalMoves() []Position {
+	if n.board == nil {
+		return []Position{}
+	}
+	moves := make(map[Position]bool)
+	n.collectSteps(moves, knightOffsets)
+	return sortedPositions(moves)
+}
+
+func (q *Queen) IsLegalMove(dest Position) bool {
+	if q.board == nil {
+		// Not on the board.
+		return false
+	}
+	if dest == q.position {
+		// Can stay put.
+		return true
+	}
+	if !q.checkDestination(dest) {
+		// This is off the board or running into a piece on this piece's side.
+		return false
+	}
+	// A Queen combines the Rook and Bishop directions.
+	return q.checkRay(dest, 1, 0) || q.checkRay(dest, -1, 0) ||
+		q.checkRay(dest, 0, 1) || q.checkRay(dest, 0, -1) ||
+		q.checkRay(dest, 1, 1) || q.checkRay(dest, -1, 1) ||
+		q.checkRay(dest, 1, -1) || q.checkRay(dest, -1, -1)
+}
+
+func (q *Queen) LegalMoves() []Position {
+	if q.board == nil {
+		return []Position{}
+	}
+	return q.slideMoves(kingOffsets)
+}
+
+func (k *King) IsLegalMove(dest Position) bool {
+	if k.board == nil {
+		// Not on the board.
+		return false
+	}
+	if dest == k.position {
+		// Can stay put.
 		return true
 	}
-	upLeftSpaces := make([]Position, 0)
-	search = b.position.Move(-1, 1)
-	for search != dest && search != b.position {
-		upLeftSpaces = append(upLeftSpaces, search)
-		search = search.Move(-1, 1)
+	if !k.checkDestination(dest) {
+		// This is off the board or running into a piece on this piece's side.
+		return false
+	}
+	for _, o := range kingOffsets {
+		if k.checkStep(dest, o[0], o[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *King) LegalMoves() []Position {
+	if k.board == nil {
+		return []Position{}
+	}
+	moves := make(map[Position]bool)
+	k.collectSteps(moves, kingOffsets)
+	return sortedPositions(moves)
+}
+
+// forward returns the rank direction this Pawn advances in.  White moves
+// up the board and Black moves down.
+func (p *Pawn) forward() int {
+	if p.color == BLACK {
+		return -1
+	}
+	return 1
+}
+
+// startRank returns the rank this Pawn may make a double step from, which
+// is the second rank from its own side of the board.
+func (p *Pawn) startRank() int {
+	if p.color == BLACK {
+		return p.board.Height() - 2
+	}
+	return 1
+}
+
+// checkEnPassant returns true if dest is the board's en passant square, one
+// diagonal step forward, with an enemy Pawn just behind it.
+func (p *Pawn) checkEnPassant(dest Position) bool {
+	ep := p.board.enPassant
+	if ep == nil || *ep != dest {
+		return false
 	}
-	if search == dest && checkClearSpaces(upLeftSpaces, b.board) {
-		// Clear path to up left.
+	if !p.checkStep(dest, 1, p.forward()) && !p.checkStep(dest, -1, p.forward()) {
+		return false
+	}
+	behind, ok := p.board.Step(dest, 0, -p.forward())
+	if !ok {
+		return false
+	}
+	victim, isPawn := p.board.GetPieceAtPosition(behind).(*Pawn)
+	return isPawn && victim.GetColor() != p.color
+}
+
+func (p *Pawn) IsLegalMove(dest Position) bool {
+	if p.board == nil {
+		// Not on the board.
+		return false
+	}
+	if dest == p.position {
+		// Can stay put.
 		return true
 	}
-	downRightSpaces := make([]Position, 0)
-	search = b.position.Move(1, -1)
-	for search != dest && search != b.position {
-		downRightSpaces = append(downRightSpaces, search)
-		search = search.Move(1, -1)
+	forward := p.forward()
+	destPiece := p.board.GetPieceAtPosition(dest)
+	if destPiece != nil {
+		if destPiece.GetColor() == p.GetColor() {
+			// This is running into a piece on this piece's side.
+			return false
+		}
+		// Pawns only capture diagonally forward.
+		return p.checkStep(dest, 1, forward) || p.checkStep(dest, -1, forward)
+	}
+	if p.checkEnPassant(dest) {
+		return true
 	}
-	if search == dest && checkClearSpaces(downRightSpaces, b.board) {
-		// Clear path to down right.
+	if p.checkStep(dest, 0, forward) {
 		return true
 	}
-	downLeftSpaces := make([]Position, 0)
-	search = b.position.Move(-1, -1)
-	for search != dest && search != b.position {
-		downLeftSpaces = append(downLeftSpaces, search)
-		search = search.Move(-1, -1)
+	// A double step is only allowed from the starting rank over an empty square.
+	oneStep, ok := p.board.Step(p.position, 0, forward)
+	return ok && p.position.rank == p.startRank() && p.checkStep(dest, 0, 2*forward) &&
+		p.board.GetPieceAtPosition(oneStep) == nil
+}
+
+func (p *Pawn) LegalMoves() []Position {
+	if p.board == nil {
+		return []Position{}
+	}
+	forward := p.forward()
+	// Pawn moves depend on occupancy in ways collectSteps does not model,
+	// so check each candidate in full.
+	moves := make(map[Position]bool)
+	for _, o := range [][2]int{{0, forward}, {0, 2 * forward}, {1, forward}, {-1, forward}} {
+		dest, ok := p.board.Step(p.position, o[0], o[1])
+		if ok && dest != p.position && p.IsLegalMove(dest) {
+			moves[dest] = true
+		}
 	}
-	return search == dest && checkClearSpaces(downLeftSpaces, b.board)
+	return sortedPositions(moves)
 }
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -98,3 +99,273 @@ func TestBishopMovement(t *testing.T) {
 		}
 	}
 }
+
+var knightMovementTestCases = []struct {
+	start          string
+	color          Color
+	dest           string
+	whitePositions []string
+	blackPositions []string
+	want           bool
+}{
+	{"d4", WHITE, "d4", []string{}, []string{}, true},
+	{"d4", WHITE, "e6", []string{}, []string{}, true},
+	{"d4", WHITE, "f5", []string{}, []string{}, true},
+	{"d4", WHITE, "f3", []string{}, []string{}, true},
+	{"d4", WHITE, "e2", []string{}, []string{}, true},
+	{"d4", WHITE, "c2", []string{}, []string{}, true},
+	{"d4", WHITE, "b3", []string{}, []string{}, true},
+	{"d4", WHITE, "b5", []string{}, []string{}, true},
+	{"d4", WHITE, "c6", []string{}, []string{}, true},
+	{"d4", WHITE, "d6", []string{}, []string{}, false},
+	{"d4", WHITE, "e5", []string{}, []string{}, false},
+	{"d4", WHITE, "f6", []string{}, []string{}, false},
+	{"a1", WHITE, "h3", []string{}, []string{}, true},           // Wraparound left
+	{"a1", WHITE, "g8", []string{}, []string{}, true},           // Wraparound both edges
+	{"h8", WHITE, "a2", []string{}, []string{}, true},           // Wraparound right and top
+	{"d4", WHITE, "e6", []string{"d5", "e5"}, []string{}, true}, // Jumps
+	{"d4", WHITE, "e6", []string{"e6"}, []string{}, false},
+	{"d4", WHITE, "e6", []string{}, []string{"e6"}, true}, // Capture
+	{"d4", BLACK, "e6", []string{}, []string{"e6"}, false},
+	{"d4", BLACK, "e6", []string{"e6"}, []string{}, true}, // Capture
+}
+
+func TestKnightMovement(t *testing.T) {
+	for _, tc := range knightMovementTestCases {
+		r := NewKnight(tc.color)
+		b := NewBoard()
+		mustPlace(t, b, r, tc.start)
+
+		for _, pos := range tc.whitePositions {
+			mustPlace(t, b, NewKnight(WHITE), pos)
+		}
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewKnight(BLACK), pos)
+		}
+		got := r.IsLegalMove(mustPosition(t, tc.dest))
+		if got != tc.want {
+			t.Errorf("Case %v failed", tc)
+		}
+	}
+}
+
+var queenMovementTestCases = []struct {
+	start          string
+	color          Color
+	dest           string
+	whitePositions []string
+	blackPositions []string
+	want           bool
+}{
+	{"b2", WHITE, "b2", []string{}, []string{}, true},
+	{"b2", WHITE, "b7", []string{}, []string{}, true},
+	{"b2", WHITE, "g2", []string{}, []string{}, true},
+	{"b2", WHITE, "f6", []string{}, []string{}, true},
+	{"b2", WHITE, "a3", []string{}, []string{}, true},
+	{"b2", WHITE, "c4", []string{}, []string{}, false},
+	{"b2", WHITE, "d3", []string{}, []string{}, false},
+	{"b2", WHITE, "d8", []string{}, []string{}, true},     // Wraparound diagonal
+	{"b2", WHITE, "h2", []string{"c2"}, []string{}, true}, // Wraparound left
+	{"b2", WHITE, "h2", []string{"c2", "a2"}, []string{}, false},
+	{"b2", WHITE, "e5", []string{"e5"}, []string{}, false},
+	{"b2", WHITE, "e5", []string{}, []string{"e5"}, true}, // Capture
+	{"b2", BLACK, "e5", []string{}, []string{"e5"}, false},
+	{"b2", BLACK, "e5", []string{"e5"}, []string{}, true}, // Capture
+	{"b2", WHITE, "b5", []string{"b4"}, []string{}, true}, // Wraparound down
+	{"b2", WHITE, "b5", []string{"b4"}, []string{"b8"}, false},
+}
+
+func TestQueenMovement(t *testing.T) {
+	for _, tc := range queenMovementTestCases {
+		r := NewQueen(tc.color)
+		b := NewBoard()
+		mustPlace(t, b, r, tc.start)
+
+		for _, pos := range tc.whitePositions {
+			mustPlace(t, b, NewQueen(WHITE), pos)
+		}
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewQueen(BLACK), pos)
+		}
+		got := r.IsLegalMove(mustPosition(t, tc.dest))
+		if got != tc.want {
+			t.Errorf("Case %v failed", tc)
+		}
+	}
+}
+
+var kingMovementTestCases = []struct {
+	start          string
+	color          Color
+	dest           string
+	whitePositions []string
+	blackPositions []string
+	want           bool
+}{
+	{"d4", WHITE, "d4", []string{}, []string{}, true},
+	{"d4", WHITE, "d5", []string{}, []string{}, true},
+	{"d4", WHITE, "e5", []string{}, []string{}, true},
+	{"d4", WHITE, "e4", []string{}, []string{}, true},
+	{"d4", WHITE, "e3", []string{}, []string{}, true},
+	{"d4", WHITE, "d3", []string{}, []string{}, true},
+	{"d4", WHITE, "c3", []string{}, []string{}, true},
+	{"d4", WHITE, "c4", []string{}, []string{}, true},
+	{"d4", WHITE, "c5", []string{}, []string{}, true},
+	{"d4", WHITE, "d6", []string{}, []string{}, false},
+	{"d4", WHITE, "f4", []string{}, []string{}, false},
+	{"a1", WHITE, "h8", []string{}, []string{}, true}, // Wraparound corner
+	{"a1", WHITE, "a8", []string{}, []string{}, true}, // Wraparound down
+	{"d4", WHITE, "d5", []string{"d5"}, []string{}, false},
+	{"d4", WHITE, "d5", []string{}, []string{"d5"}, true}, // Capture
+	{"d4", BLACK, "d5", []string{}, []string{"d5"}, false},
+	{"d4", BLACK, "d5", []string{"d5"}, []string{}, true}, // Capture
+}
+
+func TestKingMovement(t *testing.T) {
+	for _, tc := range kingMovementTestCases {
+		r := NewKing(tc.color)
+		b := NewBoard()
+		mustPlace(t, b, r, tc.start)
+
+		for _, pos := range tc.whitePositions {
+			mustPlace(t, b, NewKing(WHITE), pos)
+		}
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewKing(BLACK), pos)
+		}
+		got := r.IsLegalMove(mustPosition(t, tc.dest))
+		if got != tc.want {
+			t.Errorf("Case %v failed", tc)
+		}
+	}
+}
+
+var pawnMovementTestCases = []struct {
+	start          string
+	color          Color
+	dest           string
+	whitePositions []string
+	blackPositions []string
+	want           bool
+}{
+	{"d2", WHITE, "d2", []string{}, []string{}, true},
+	{"d2", WHITE, "d3", []string{}, []string{}, true},
+	{"d2", WHITE, "d4", []string{}, []string{}, true}, // Double step from start
+	{"d3", WHITE, "d5", []string{}, []string{}, false},
+	{"d2", WHITE, "d1", []string{}, []string{}, false},
+	{"d2", WHITE, "e3", []string{}, []string{}, false},
+	{"d2", WHITE, "d3", []string{}, []string{"d3"}, false},
+	{"d2", WHITE, "d4", []string{}, []string{"d3"}, false},
+	{"d2", WHITE, "e3", []string{}, []string{"e3"}, true}, // Capture
+	{"d2", WHITE, "c3", []string{}, []string{"c3"}, true}, // Capture
+	{"d2", WHITE, "c3", []string{"c3"}, []string{}, false},
+	{"d2", WHITE, "c1", []string{}, []string{"c1"}, false},
+	{"h8", WHITE, "h1", []string{}, []string{}, true},     // Wraparound up
+	{"h4", WHITE, "a5", []string{}, []string{"a5"}, true}, // Wraparound capture
+	{"d7", BLACK, "d6", []string{}, []string{}, true},
+	{"d7", BLACK, "d5", []string{}, []string{}, true}, // Double step from start
+	{"d6", BLACK, "d4", []string{}, []string{}, false},
+	{"d7", BLACK, "d8", []string{}, []string{}, false},
+	{"d7", BLACK, "e6", []string{"e6"}, []string{}, true}, // Capture
+	{"d7", BLACK, "e6", []string{}, []string{"e6"}, false},
+	{"a1", BLACK, "a8", []string{}, []string{}, true}, // Wraparound down
+}
+
+func TestPawnMovement(t *testing.T) {
+	for _, tc := range pawnMovementTestCases {
+		r := NewPawn(tc.color)
+		b := NewBoard()
+		mustPlace(t, b, r, tc.start)
+
+		for _, pos := range tc.whitePositions {
+			mustPlace(t, b, NewPawn(WHITE), pos)
+		}
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewPawn(BLACK), pos)
+		}
+		got := r.IsLegalMove(mustPosition(t, tc.dest))
+		if got != tc.want {
+			t.Errorf("Case %v failed", tc)
+		}
+	}
+}
+
+var legalMovesTestCases = []struct {
+	newPiece       func(c Color) ChessPiece
+	start          string
+	whitePositions []string
+	blackPositions []string
+	want           []string
+}{
+	{knightPiece, "d4", []string{"e6"}, []string{"c6"}, []string{"c2", "e2", "b3", "f3", "b5", "f5", "c6"}},
+	{kingPiece, "a1", []string{"b1", "b2", "a2"}, []string{"h8"}, []string{"h1", "h2", "a8", "b8", "h8"}},
+	{pawnPiece, "d2", []string{}, []string{"e3"}, []string{"d3", "e3", "d4"}},
+	{pawnPiece, "d2", []string{}, []string{"d3"}, []string{}},
+	{rookPiece, "a1", []string{"a2", "c1"}, []string{"a7"}, []string{"b1", "h1", "g1", "f1", "e1", "d1", "a8", "a7"}},
+	{bishopPiece, "a1", []string{"b2", "h2"}, []string{"b8"}, []string{"h8", "g7", "f6", "e5", "d4", "c3", "b8"}},
+	{queenPiece, "a1", []string{"a2", "b1", "b2", "h1", "h2", "a8"}, []string{"b8", "h8"}, []string{"b8", "h8"}},
+}
+
+func TestLegalMoves(t *testing.T) {
+	for _, tc := range legalMovesTestCases {
+		p := tc.newPiece(WHITE)
+		b := NewBoard()
+		mustPlace(t, b, p, tc.start)
+
+		for _, pos := range tc.whitePositions {
+			mustPlace(t, b, NewRook(WHITE), pos)
+		}
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewRook(BLACK), pos)
+		}
+		want := make([]Position, 0)
+		for _, pos := range tc.want {
+			want = append(want, mustPosition(t, pos))
+		}
+		sortPositions(want)
+		got := p.LegalMoves()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v.LegalMoves() = %v, wanted %v", p, got, want)
+		}
+	}
+}
+
+// TestLegalMovesMatchesIsLegalMove checks every piece on a crowded board of
+// each Topology, confirming LegalMoves agrees with IsLegalMove on every square.
+func TestLegalMovesMatchesIsLegalMove(t *testing.T) {
+	newPieces := []func(c Color) ChessPiece{rookPiece, bishopPiece, knightPiece, queenPiece, kingPiece, pawnPiece}
+	for _, topology := range []Topology{STANDARD, HORIZONTAL_CYLINDER, VERTICAL_CYLINDER, TORUS} {
+		for _, dims := range [][2]int{{8, 8}, {5, 5}, {10, 8}} {
+			b, err := NewSizedBoard(dims[0], dims[1], topology)
+			if err != nil {
+				t.Fatalf("NewSizedBoard returned err %v", err)
+			}
+			mustPlace(t, b, NewRook(WHITE), "c3")
+			mustPlace(t, b, NewRook(BLACK), "e2")
+			mustPlace(t, b, NewRook(BLACK), "a5")
+			mustPlace(t, b, NewRook(WHITE), "d5")
+			for i, newPiece := range newPieces {
+				for _, c := range []Color{WHITE, BLACK} {
+					p := newPiece(c)
+					start := []string{"b2", "a1", "e5", "d4", "b4", "c2"}[i]
+					mustPlace(t, b, p, start)
+					want := make([]Position, 0)
+					for rank := 0; rank < b.Height(); rank++ {
+						for file := 0; file < b.Width(); file++ {
+							dest := Position{rank: rank, file: file}
+							if dest != *p.GetPosition() && p.IsLegalMove(dest) {
+								want = append(want, dest)
+							}
+						}
+					}
+					if got := p.LegalMoves(); !reflect.DeepEqual(got, want) {
+						t.Errorf("%v %vx%v %v.LegalMoves() = %v, wanted %v", topology, dims[0], dims[1], p, got, want)
+					}
+					if _, err := b.RemovePiece(*p.GetPosition()); err != nil {
+						t.Fatalf("RemovePiece(%v) returned err %v", p.GetPosition(), err)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// fenLetter returns the Forsyth-Edwards Notation letter for piece, upper
+// case for White and lower case for Black.
+func fenLetter(piece ChessPiece) rune {
+	var letter rune
+	switch piece.(type) {
+	case *Rook:
+		letter = 'r'
+	case *Bishop:
+		letter = 'b'
+	case *Knight:
+		letter = 'n'
+	case *Queen:
+		letter = 'q'
+	case *King:
+		letter = 'k'
+	case *Pawn:
+		letter = 'p'
+	default:
+		panic(fmt.Sprintf("no FEN letter for %v", piece))
+	}
+	if piece.GetColor() == WHITE {
+		return unicode.ToUpper(letter)
+	}
+	return letter
+}
+
+// NewPieceFromLetter builds a new off-board piece from its Forsyth-Edwards
+// Notation letter.  Upper case letters are White and lower case are Black.
+func NewPieceFromLetter(letter rune) (ChessPiece, error) {
+	c := BLACK
+	if unicode.IsUpper(letter) {
+		c = WHITE
+	}
+	switch unicode.ToLower(letter) {
+	case 'r':
+		return NewRook(c), nil
+	case 'b':
+		return NewBishop(c), nil
+	case 'n':
+		return NewKnight(c), nil
+	case 'q':
+		return NewQueen(c), nil
+	case 'k':
+		return NewKing(c), nil
+	case 'p':
+		return NewPawn(c), nil
+	}
+	return nil, fmt.Errorf("NewPieceFromLetter: unknown piece letter %c", letter)
+}
+
+// ToFEN emits this board in Forsyth-Edwards Notation, including side to
+// move, castling, en passant and move counter fields.  Runs of more than
+// nine empty squares on wide boards are written as multi-digit numbers.
+func (b *Board) ToFEN() string {
+	var sb strings.Builder
+	for rank := b.height - 1; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < b.width; file++ {
+			piece := b.positions[Position{rank: rank, file: file}]
+			if piece == nil {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteRune(fenLetter(piece))
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+		if rank > 0 {
+			sb.WriteRune('/')
+		}
+	}
+	side := "w"
+	if b.sideToMove == BLACK {
+		side = "b"
+	}
+	enPassant := "-"
+	if b.enPassant != nil {
+		enPassant = b.enPassant.String()
+	}
+	return fmt.Sprintf("%v %v %v %v %v %v", sb.String(), side, b.castling, enPassant, b.halfmoveClock, b.fullmoveNumber)
+}
+
+// FromFEN replaces the contents of this board with the position described
+// in Forsyth-Edwards Notation.  The placement field must match the board's
+// width and height.  Trailing fields may be omitted, defaulting to White to
+// move, no castling, no en passant square and counters of 0 and 1.  On error
+// the board is left unchanged, and on success no moves can be undone.
+func (b *Board) FromFEN(fen string) error {
+	fields := strings.Fields(fen)
+	if len(fields) < 1 || len(fields) > 6 {
+		return fmt.Errorf("FromFEN: expected 1-6 fields, got %v", len(fields))
+	}
+	positions, err := b.parsePlacement(fields[0])
+	if err != nil {
+		return err
+	}
+	sideToMove := WHITE
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "w":
+		case "b":
+			sideToMove = BLACK
+		default:
+			return fmt.Errorf("FromFEN: side to move should be w or b, got %v", fields[1])
+		}
+	}
+	castling := "-"
+	if len(fields) > 2 {
+		castling = fields[2]
+		if castling != "-" && strings.Trim(castling, "KQkq") != "" {
+			return fmt.Errorf("FromFEN: castling should be - or letters from KQkq, got %v", castling)
+		}
+	}
+	var enPassant *Position
+	if len(fields) > 3 && fields[3] != "-" {
+		enPassant, err = NewPosition(fields[3])
+		if err != nil {
+			return fmt.Errorf("FromFEN: bad en passant square: %w", err)
+		}
+		if !b.Contains(*enPassant) {
+			return fmt.Errorf("FromFEN: en passant square %v is off the %vx%v board", enPassant, b.width, b.height)
+		}
+	}
+	counters := []int{0, 1}
+	for i := 4; i < len(fields); i++ {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil || n < 0 {
+			return fmt.Errorf("FromFEN: move counters should be non-negative numbers, got %v", fields[i])
+		}
+		counters[i-4] = n
+	}
+
+	for p, piece := range b.positions {
+		piece.remove()
+		b.clearSquare(p)
+	}
+	for p, piece := range positions {
+		b.setSquare(p, piece)
+		piece.place(b, p)
+	}
+	b.sideToMove = sideToMove
+	b.castling = castling
+	b.enPassant = enPassant
+	b.halfmoveClock = counters[0]
+	b.fullmoveNumber = counters[1]
+	b.clearHistory()
+	return nil
+}
+
+// parsePlacement reads the piece placement field of a FEN string into a new
+// set of off-board pieces keyed by where they belong.
+func (b *Board) parsePlacement(placement string) (map[Position]ChessPiece, error) {
+	rows := strings.Split(placement, "/")
+	if len(rows) != b.height {
+		return nil, fmt.Errorf("FromFEN: expected %v ranks, got %v", b.height, len(rows))
+	}
+	positions := make(map[Position]ChessPiece)
+	for i, row := range rows {
+		rank := b.height - 1 - i
+		file := 0
+		empty := 0
+		for _, c := range row {
+			if unicode.IsDigit(c) {
+				empty = empty*10 + int(c-'0')
+				if empty == 0 {
+					return nil, fmt.Errorf("FromFEN: rank %v has a zero or leading zero empty count", rank+1)
+				}
+				continue
+			}
+			file += empty
+			empty = 0
+			piece, err := NewPieceFromLetter(c)
+			if err != nil {
+				return nil, fmt.Errorf("FromFEN: %w", err)
+			}
+			if file < b.width {
+				positions[Position{rank: rank, file: file}] = piece
+			}
+			file++
+		}
+		file += empty
+		if file != b.width {
+			return nil, fmt.Errorf("FromFEN: rank %v has %v files, expected %v", rank+1, file, b.width)
+		}
+	}
+	return positions, nil
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+var fenRoundTripTestCases = []struct {
+	width, height int
+	fen           string
+}{
+	{8, 8, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+	{8, 8, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},
+	{8, 8, "8/8/8/8/8/2B5/8/7r w - - 0 1"},
+	{8, 8, "4k3/8/8/8/8/8/8/4K2R w K - 12 40"},
+	{5, 5, "rnbqk/ppppp/5/PPPPP/RNBQK w - - 0 1"},
+	{10, 8, "r9/10/10/10/10/10/10/9R b - - 3 7"},
+	{16, 16, "16/16/16/16/16/16/16/16/16/16/16/16/16/16/16/k14K w - - 0 1"},
+}
+
+func TestFENRoundTrip(t *testing.T) {
+	for _, tc := range fenRoundTripTestCases {
+		b, err := NewSizedBoard(tc.width, tc.height, TORUS)
+		if err != nil {
+			t.Fatalf("NewSizedBoard(%v,%v) returned err %v", tc.width, tc.height, err)
+		}
+		if err := b.FromFEN(tc.fen); err != nil {
+			t.Errorf("FromFEN(%v) returned err %v", tc.fen, err)
+			continue
+		}
+		if got := b.ToFEN(); got != tc.fen {
+			t.Errorf("FromFEN(%v).ToFEN() = %v", tc.fen, got)
+		}
+	}
+}
+
+func TestFromFENPlacesPieces(t *testing.T) {
+	b := NewBoard()
+	mustPlace(t, b, NewQueen(WHITE), "a1")
+	old := b.GetPieceAtPosition(mustPosition(t, "a1"))
+
+	if err := b.FromFEN("8/8/8/8/8/2B5/8/7r b"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+
+	if pos := old.GetPosition(); pos != nil {
+		t.Errorf("Piece from before FromFEN should be off the board, was at %v", pos)
+	}
+	if got := b.GetPieceAtPosition(mustPosition(t, "a1")); got != nil {
+		t.Errorf("GetPieceAtPosition(a1) = %v, wanted nil", got)
+	}
+	rook, ok := b.GetPieceAtPosition(mustPosition(t, "h1")).(*Rook)
+	if !ok || rook.GetColor() != BLACK {
+		t.Fatalf("GetPieceAtPosition(h1) = %v, wanted Black Rook", rook)
+	}
+	assertPieceConsistent(t, b, rook, mustPosition(t, "h1"))
+	bishop, ok := b.GetPieceAtPosition(mustPosition(t, "c3")).(*Bishop)
+	if !ok || bishop.GetColor() != WHITE {
+		t.Fatalf("GetPieceAtPosition(c3) = %v, wanted White Bishop", bishop)
+	}
+	if b.SideToMove() != BLACK {
+		t.Errorf("SideToMove() = %v, wanted BLACK", b.SideToMove())
+	}
+	if got, want := b.ToFEN(), "8/8/8/8/8/2B5/8/7r b - - 0 1"; got != want {
+		t.Errorf("ToFEN() = %v, wanted %v", got, want)
+	}
+}
+
+var fromFENErrorTestCases = []struct {
+	fen       string
+	wantError error
+}{
+	{"", errors.New("FromFEN: expected 1-6 fields, got 0")},
+	{"8/8/8/8/8/8/8/8 w - - 0 1 x", errors.New("FromFEN: expected 1-6 fields, got 7")},
+	{"8/8/8/8/8/8/8", errors.New("FromFEN: expected 8 ranks, got 7")},
+	{"8/8/8/8/8/8/8/7", errors.New("FromFEN: rank 1 has 7 files, expected 8")},
+	{"8/8/8/8/8/8/8/9", errors.New("FromFEN: rank 1 has 9 files, expected 8")},
+	{"8/8/8/8/8/8/8/7rr", errors.New("FromFEN: rank 1 has 9 files, expected 8")},
+	{"8/8/8/8/8/8/8/08", errors.New("FromFEN: rank 1 has a zero or leading zero empty count")},
+	{"8/8/8/8/8/8/8/7x", errors.New("FromFEN: NewPieceFromLetter: unknown piece letter x")},
+	{"8/8/8/8/8/8/8/8 x", errors.New("FromFEN: side to move should be w or b, got x")},
+	{"8/8/8/8/8/8/8/8 w KX", errors.New("FromFEN: castling should be - or letters from KQkq, got KX")},
+	{"8/8/8/8/8/8/8/8 w - i9", errors.New("FromFEN: en passant square i9 is off the 8x8 board")},
+	{"8/8/8/8/8/8/8/8 w - - -1", errors.New("FromFEN: move counters should be non-negative numbers, got -1")},
+}
+
+func TestFromFENErrors(t *testing.T) {
+	for _, tc := range fromFENErrorTestCases {
+		b := NewBoard()
+		rook := NewRook(WHITE)
+		mustPlace(t, b, rook, "d4")
+		err := b.FromFEN(tc.fen)
+		// Generally undesirable, but want to verify error strings.
+		if err == nil || err.Error() != tc.wantError.Error() {
+			t.Errorf("FromFEN(%v) returned err %v, wanted %v", tc.fen, err, tc.wantError)
+		}
+		// The board must be untouched by a failed load.
+		assertPieceConsistent(t, b, rook, mustPosition(t, "d4"))
+	}
+}
+
+func TestMovePieceUpdatesFEN(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4k3/3p4/8/4P3/8/8/8/4K3 b - - 5 20"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	blackPawn := b.GetPieceAtPosition(mustPosition(t, "d7"))
+	whitePawn := b.GetPieceAtPosition(mustPosition(t, "e5"))
+
+	if err := b.MovePiece(blackPawn, mustPosition(t, "d5")); err != nil {
+		t.Fatalf("MovePiece(d7-d5) returned err %v", err)
+	}
+	if got, want := b.ToFEN(), "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 21"; got != want {
+		t.Errorf("ToFEN() after double step = %v, wanted %v", got, want)
+	}
+
+	if !whitePawn.IsLegalMove(mustPosition(t, "d6")) {
+		t.Errorf("%v should be able to capture en passant on d6", whitePawn)
+	}
+	if err := b.MovePiece(whitePawn, mustPosition(t, "d6")); err != nil {
+		t.Fatalf("MovePiece(e5xd6) returned err %v", err)
+	}
+	if pos := blackPawn.GetPosition(); pos != nil {
+		t.Errorf("Pawn captured en passant should have nil position, was %v", pos)
+	}
+	if got, want := b.ToFEN(), "4k3/8/3P4/8/8/8/8/4K3 b - - 0 21"; got != want {
+		t.Errorf("ToFEN() after en passant = %v, wanted %v", got, want)
+	}
+
+	king := b.GetPieceAtPosition(mustPosition(t, "e8"))
+	if err := b.MovePiece(king, mustPosition(t, "f8")); err != nil {
+		t.Fatalf("MovePiece(e8-f8) returned err %v", err)
+	}
+	if got, want := b.ToFEN(), "5k2/8/3P4/8/8/8/8/4K3 w - - 1 22"; got != want {
+		t.Errorf("ToFEN() after quiet move = %v, wanted %v", got, want)
+	}
+	if b.SideToMove() != WHITE {
+		t.Errorf("SideToMove() = %v after a Black move, wanted WHITE", b.SideToMove())
+	}
+}
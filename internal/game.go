@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// OutOfTurnError is returned when a piece is moved while it is the other
+// side's turn.
+type OutOfTurnError struct {
+	// Piece is the piece that tried to move.
+	Piece ChessPiece
+
+	// SideToMove is the Color whose turn it actually was.
+	SideToMove Color
+}
+
+func (e *OutOfTurnError) Error() string {
+	return fmt.Sprintf("Move: %v cannot move, it is %v's turn", e.Piece, e.SideToMove)
+}
+
+// Game wraps a Board, enforcing turn order and recording each move made.
+type Game struct {
+	board *Board
+
+	// history lists every move made through this Game in order.
+	history []Move
+
+	// redo lists the moves taken back by Undo, most recent last.
+	redo []Move
+}
+
+// NewGame starts a Game on b.  The side to move is taken from b, so load a
+// FEN with Black to move if Black should go first.
+func NewGame(b *Board) *Game {
+	return &Game{
+		board:   b,
+		history: make([]Move, 0),
+	}
+}
+
+// Board returns the Board this Game is played on.
+func (g *Game) Board() *Board {
+	return g.board
+}
+
+// SideToMove returns the Color whose turn it is.
+func (g *Game) SideToMove() Color {
+	return g.board.SideToMove()
+}
+
+// Move moves piece to dest.  Returns an *OutOfTurnError if it is not the
+// piece's turn, or the Board's error if the move is not legal.
+func (g *Game) Move(piece ChessPiece, dest Position) error {
+	if piece.GetColor() != g.SideToMove() {
+		return &OutOfTurnError{
+			Piece:      piece,
+			SideToMove: g.SideToMove(),
+		}
+	}
+	r, err := g.board.movePiece(piece, dest)
+	if err != nil {
+		return err
+	}
+	g.history = append(g.history, r.Move)
+	g.redo = g.redo[:0]
+	return nil
+}
+
+// Pass gives up the current side's turn without moving.  The pass is
+// recorded in the history as a Move with a nil Piece.
+func (g *Game) Pass() {
+	g.board.pass()
+	g.history = append(g.history, Move{})
+	g.redo = g.redo[:0]
+}
+
+// Undo takes back the most recent move or pass, removing it from the
+// history.  See Board.Undo.  Returns an error, changing nothing, if the
+// Board's most recent move was not made through this Game, such as one made
+// by Board.MovePiece directly.
+func (g *Game) Undo() (Move, error) {
+	if len(g.history) == 0 {
+		return Move{}, fmt.Errorf("Undo: no move to take back")
+	}
+	last := g.history[len(g.history)-1]
+	if m, ok := g.board.LastMove(); !ok || m != last {
+		return Move{}, fmt.Errorf("Undo: the board's last move was not made through this Game")
+	}
+	m, err := g.board.Undo()
+	if err != nil {
+		return m, err
+	}
+	g.history = g.history[:len(g.history)-1]
+	g.redo = append(g.redo, m)
+	return m, nil
+}
+
+// Redo makes the move most recently taken back by Undo again, adding it back
+// to the history.  See Board.Redo.  Returns an error, changing nothing, if
+// the Board would redo a different move.
+func (g *Game) Redo() (Move, error) {
+	if len(g.redo) == 0 {
+		return Move{}, fmt.Errorf("Redo: no move to make again")
+	}
+	next := g.redo[len(g.redo)-1]
+	if n := len(g.board.redo); n == 0 || g.board.redo[n-1].Move != next {
+		return Move{}, fmt.Errorf("Redo: the board's next move was not taken back through this Game")
+	}
+	m, err := g.board.Redo()
+	if err != nil {
+		return m, err
+	}
+	g.redo = g.redo[:len(g.redo)-1]
+	g.history = append(g.history, m)
+	return m, nil
+}
+
+// History returns every move made so far, oldest first.
+func (g *Game) History() []Move {
+	return append([]Move{}, g.history...)
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGameMove(t *testing.T) {
+	b := NewBoard()
+	white := NewRook(WHITE)
+	mustPlace(t, b, white, "a1")
+	black := NewRook(BLACK)
+	mustPlace(t, b, black, "a8")
+	g := NewGame(b)
+
+	if err := g.Move(white, mustPosition(t, "a4")); err != nil {
+		t.Fatalf("Move(%v, a4) returned err %v", white, err)
+	}
+	if err := g.Move(black, mustPosition(t, "a4")); err != nil {
+		t.Fatalf("Move(%v, a4) returned err %v", black, err)
+	}
+	if g.SideToMove() != WHITE {
+		t.Errorf("SideToMove() = %v, wanted White", g.SideToMove())
+	}
+	want := []Move{
+		{Piece: white, From: mustPosition(t, "a1"), To: mustPosition(t, "a4")},
+		{Piece: black, From: mustPosition(t, "a8"), To: mustPosition(t, "a4"), Captured: white},
+	}
+	if got := g.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("History() = %v, wanted %v", got, want)
+	}
+}
+
+func TestGameMoveOutOfTurn(t *testing.T) {
+	b := NewBoard()
+	white := NewRook(WHITE)
+	mustPlace(t, b, white, "a1")
+	black := NewRook(BLACK)
+	mustPlace(t, b, black, "h8")
+	g := NewGame(b)
+
+	err := g.Move(black, mustPosition(t, "h5"))
+
+	var outOfTurn *OutOfTurnError
+	if !errors.As(err, &outOfTurn) {
+		t.Fatalf("Move out of turn returned err %v, wanted *OutOfTurnError", err)
+	}
+	if outOfTurn.Piece != black || outOfTurn.SideToMove != WHITE {
+		t.Errorf("OutOfTurnError = %+v, wanted Piece %v and SideToMove White", outOfTurn, black)
+	}
+	if got, want := err.Error(), "Move: Black Rook at h8 cannot move, it is White's turn"; got != want {
+		t.Errorf("Error() = %v, wanted %v", got, want)
+	}
+	assertPieceConsistent(t, b, black, mustPosition(t, "h8"))
+	if got := g.History(); len(got) != 0 {
+		t.Errorf("History() after rejected move = %v, wanted empty", got)
+	}
+}
+
+func TestGameMoveIllegal(t *testing.T) {
+	b := NewBoard()
+	white := NewRook(WHITE)
+	mustPlace(t, b, white, "a1")
+	g := NewGame(b)
+
+	if err := g.Move(white, mustPosition(t, "b2")); err == nil {
+		t.Errorf("Move(%v, b2) returned nil err", white)
+	}
+	if g.SideToMove() != WHITE {
+		t.Errorf("SideToMove() after illegal move = %v, wanted White", g.SideToMove())
+	}
+}
+
+func TestGamePass(t *testing.T) {
+	b := NewBoard()
+	black := NewRook(BLACK)
+	mustPlace(t, b, black, "h1")
+	b.SetSideToMove(BLACK)
+	g := NewGame(b)
+
+	if err := g.Move(black, mustPosition(t, "h3")); err != nil {
+		t.Fatalf("Move(%v, h3) returned err %v", black, err)
+	}
+	g.Pass()
+
+	if g.SideToMove() != BLACK {
+		t.Errorf("SideToMove() after pass = %v, wanted Black", g.SideToMove())
+	}
+	history := g.History()
+	if len(history) != 2 || history[1].String() != "--" {
+		t.Errorf("History() = %v, wanted a move then a pass", history)
+	}
+	if got, want := b.ToFEN(), "8/8/8/8/8/7r/8/8 b - - 2 2"; got != want {
+		t.Errorf("ToFEN() = %v, wanted %v", got, want)
+	}
+}
+
+func TestGameUndoIgnoresBoardMoves(t *testing.T) {
+	b := NewBoard()
+	rook := NewRook(WHITE)
+	mustPlace(t, b, rook, "a1")
+	g := NewGame(b)
+	if err := g.Move(rook, mustPosition(t, "a3")); err != nil {
+		t.Fatalf("Move returned err %v", err)
+	}
+	// A move made on the Board directly is not part of the Game.
+	if err := b.MovePiece(rook, mustPosition(t, "c3")); err != nil {
+		t.Fatalf("MovePiece returned err %v", err)
+	}
+
+	if _, err := g.Undo(); err == nil {
+		t.Errorf("Undo() took back a move made outside the Game")
+	}
+	if len(g.History()) != 1 || *rook.GetPosition() != mustPosition(t, "c3") {
+		t.Errorf("failed Undo() left history %v and %v", g.History(), rook)
+	}
+
+	// Once the Board's own move is undone, the Game's is next.
+	if _, err := b.Undo(); err != nil {
+		t.Fatalf("Board Undo() returned err %v", err)
+	}
+	if m, err := g.Undo(); err != nil || m.To != mustPosition(t, "a3") {
+		t.Errorf("Undo() = %v, %v, wanted a1-a3", m, err)
+	}
+	if len(g.History()) != 0 || *rook.GetPosition() != mustPosition(t, "a1") {
+		t.Errorf("Undo() left history %v and %v", g.History(), rook)
+	}
+
+	// Redo must not replay a move the Board took back on its own.
+	if err := b.MovePiece(rook, mustPosition(t, "a5")); err != nil {
+		t.Fatalf("MovePiece returned err %v", err)
+	}
+	if _, err := b.Undo(); err != nil {
+		t.Fatalf("Board Undo() returned err %v", err)
+	}
+	if _, err := g.Redo(); err == nil {
+		t.Errorf("Redo() made a move taken back outside the Game")
+	}
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// ImagePalette is the palette of every image RenderImage draws, so that
+// frames can be combined into one animated GIF.
+var ImagePalette = color.Palette{
+	color.RGBA{0xf0, 0xd9, 0xb5, 0xff}, // light square
+	color.RGBA{0xb5, 0x88, 0x63, 0xff}, // dark square
+	color.RGBA{0xf6, 0x9a, 0x8a, 0xff}, // highlighted light square
+	color.RGBA{0xd0, 0x5a, 0x4a, 0xff}, // highlighted dark square
+	color.RGBA{0xff, 0xff, 0xff, 0xff}, // white piece
+	color.RGBA{0x20, 0x20, 0x20, 0xff}, // black piece
+	color.RGBA{0xfa, 0xfa, 0xfa, 0xff}, // background
+	color.RGBA{0x40, 0x40, 0x40, 0xff}, // labels
+	color.RGBA{0xff, 0xe0, 0x40, 0xff}, // caption
+}
+
+// Indexes into ImagePalette.
+const (
+	paletteLight uint8 = iota
+	paletteDark
+	paletteLightHighlight
+	paletteDarkHighlight
+	paletteWhitePiece
+	paletteBlackPiece
+	paletteBackground
+	paletteLabel
+	paletteCaption
+)
+
+// ImageOptions controls what RenderImage draws besides the squares and
+// pieces.
+type ImageOptions struct {
+	// SquareSize is the width of each square in pixels, 32 if zero.
+	SquareSize int
+
+	// MovesOf highlights the squares this piece can move to, if set.
+	MovesOf ChessPiece
+
+	// AttackedBy highlights the squares pieces of this Color attack, unless
+	// it is EMPTY.
+	AttackedBy Color
+
+	// Caption is written on a highlighted band above the board, if set.  It
+	// is cut off at the board's width, so every image of a board is the same
+	// size.
+	Caption string
+}
+
+// RenderImage draws the board as a paletted image using ImagePalette, with
+// the highest rank at the top, rank numbers down the left and file letters
+// along the bottom.  Pieces are discs marked with their FEN letter.
+func (b *Board) RenderImage(opts ImageOptions) *image.Paletted {
+	size := opts.SquareSize
+	if size <= 0 {
+		size = 32
+	}
+	highlight := b.highlighted(opts.MovesOf, opts.AttackedBy)
+	pad := fontHeight
+	left := textWidth(fmt.Sprint(b.height), 1) + pad
+	top := pad
+	if opts.Caption != "" {
+		top += fontHeight + 2*pad
+	}
+	width := left + b.width*size + pad
+	height := top + b.height*size + fontHeight + 2*pad
+	img := image.NewPaletted(image.Rect(0, 0, width, height), ImagePalette)
+	fillRect(img, 0, 0, width, height, paletteBackground)
+	if opts.Caption != "" {
+		fillRect(img, 0, 0, width, fontHeight+2*pad, paletteCaption)
+		drawText(img, opts.Caption, pad, pad, 1, paletteLabel)
+	}
+	for rank := 0; rank < b.height; rank++ {
+		y := top + (b.height-1-rank)*size
+		label := fmt.Sprint(rank + 1)
+		drawText(img, label, left-pad/2-textWidth(label, 1), y+(size-fontHeight)/2, 1, paletteLabel)
+		for file := 0; file < b.width; file++ {
+			x := left + file*size
+			pos := Position{rank: rank, file: file}
+			c := paletteLight
+			if (rank+file)%2 == 0 {
+				c = paletteDark
+			}
+			if highlight[pos] {
+				// The highlighted colors follow the plain ones.
+				c += paletteLightHighlight
+			}
+			fillRect(img, x, y, size, size, c)
+			if piece := b.positions[pos]; piece != nil {
+				drawPiece(img, piece, x, y, size)
+			}
+		}
+	}
+	for file := 0; file < b.width; file++ {
+		drawText(img, string(rune('a'+file)), left+file*size+(size-fontWidth)/2, top+b.height*size+pad, 1, paletteLabel)
+	}
+	return img
+}
+
+// drawPiece draws piece as a disc in its own color, outlined and marked
+// with its FEN letter in the other color, filling the size by size square at
+// (x, y).
+func drawPiece(img *image.Paletted, piece ChessPiece, x, y, size int) {
+	fill, ink := paletteWhitePiece, paletteBlackPiece
+	if piece.GetColor() == BLACK {
+		fill, ink = ink, fill
+	}
+	cx, cy := x+size/2, y+size/2
+	outer := size * 2 / 5
+	inner := outer - 1 - size/32
+	for py := y; py < y+size; py++ {
+		for px := x; px < x+size; px++ {
+			d := (px-cx)*(px-cx) + (py-cy)*(py-cy)
+			switch {
+			case d <= inner*inner:
+				img.SetColorIndex(px, py, fill)
+			case d <= outer*outer:
+				img.SetColorIndex(px, py, ink)
+			}
+		}
+	}
+	scale := size / 16
+	if scale < 1 {
+		scale = 1
+	}
+	drawText(img, string(fenLetter(piece)), cx-fontWidth*scale/2, cy-fontHeight*scale/2, scale, ink)
+}
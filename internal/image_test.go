@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestFontGlyphsAreRectangular(t *testing.T) {
+	for r, glyph := range font {
+		for _, row := range glyph {
+			if len(row) != fontWidth {
+				t.Errorf("glyph %q has row %q, wanted %v pixels", r, row, fontWidth)
+			}
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	testCases := []struct {
+		s     string
+		scale int
+		want  int
+	}{
+		{"", 1, 0},
+		{"a", 1, 5},
+		{"ab", 1, 11},
+		{"ab", 2, 22},
+	}
+	for _, tc := range testCases {
+		if got := textWidth(tc.s, tc.scale); got != tc.want {
+			t.Errorf("textWidth(%q, %v) = %v, wanted %v", tc.s, tc.scale, got, tc.want)
+		}
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	b := NewBoard()
+	mustPlace(t, b, NewBishop(WHITE), "c3")
+	short := b.RenderImage(ImageOptions{Caption: "Start"})
+	got := b.RenderImage(ImageOptions{SquareSize: 32, AttackedBy: WHITE, Caption: "Turn 1: Heads, rolled 7, and a caption too long to fit"})
+	if short.Bounds() != got.Bounds() {
+		t.Errorf("RenderImage with a long caption is %v, wanted the same size as a short one, %v", got.Bounds(), short.Bounds())
+	}
+
+	// squareColor returns the palette index at the corner of a square, clear
+	// of any piece drawn on it.
+	left := textWidth("8", 1) + fontHeight
+	// The caption band and the padding below it.
+	top := 4 * fontHeight
+	squareColor := func(pos string) uint8 {
+		p := mustPosition(t, pos)
+		return got.ColorIndexAt(left+p.file*32+1, top+(7-p.rank)*32+1)
+	}
+	testCases := []struct {
+		pos  string
+		want uint8
+	}{
+		{"a1", paletteDarkHighlight},
+		{"b1", paletteLight},
+		{"d4", paletteDarkHighlight},
+		{"h8", paletteDarkHighlight},
+		{"c3", paletteDark},
+		{"c4", paletteLight},
+	}
+	for _, tc := range testCases {
+		if c := squareColor(tc.pos); c != tc.want {
+			t.Errorf("RenderImage colored %v %v, wanted %v", tc.pos, c, tc.want)
+		}
+	}
+	// The Bishop's disc covers the middle of c3.
+	p := mustPosition(t, "c3")
+	if c := got.ColorIndexAt(left+p.file*32+16, top+(7-p.rank)*32+4); c != paletteBlackPiece && c != paletteWhitePiece {
+		t.Errorf("RenderImage did not draw the Bishop on c3, got color %v", c)
+	}
+	if c := got.ColorIndexAt(1, 1); c != paletteCaption {
+		t.Errorf("RenderImage caption band has color %v, wanted %v", c, paletteCaption)
+	}
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// Move describes a single legal move of a piece on a Board.
+type Move struct {
+	// Piece is the piece being moved, or nil if a side passed.
+	Piece ChessPiece
+
+	// From and To are the squares the piece moves between.
+	From, To Position
+
+	// Captured is the piece taken by this move, or nil if the move does not
+	// capture.  For en passant this Pawn was not on To.
+	Captured ChessPiece
+}
+
+// String emits the move in long algebraic form, such as b2-b4 or b2xb7.
+// A pass is written as --.
+func (m Move) String() string {
+	if m.Piece == nil {
+		return "--"
+	}
+	if m.Captured != nil {
+		return fmt.Sprintf("%vx%v", m.From, m.To)
+	}
+	return fmt.Sprintf("%v-%v", m.From, m.To)
+}
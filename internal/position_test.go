@@ -11,13 +11,19 @@ var newPositionTestCases = []struct {
 	wantError error
 }{
 	{"a1", nil},
-	{"a1b", errors.New("NewPosition: invalid string. expected len(2), got a1b")},
-	{"h-1", errors.New("NewPosition: invalid string. expected len(2), got h-1")},
-	{"A1", errors.New("NewPosition: first file character should be a-h, got A")},
-	{"i7", errors.New("NewPosition: first file character should be a-h, got i")},
+	{"a", errors.New("NewPosition: invalid string. expected a file letter and rank number, got a")},
+	{"a1b", errors.New("NewPosition: rank should be a number from 1, got 1b")},
+	{"h-1", errors.New("NewPosition: rank should be a number from 1, got -1")},
+	{"h+1", errors.New("NewPosition: rank should be a number from 1, got +1")},
+	{"h0", errors.New("NewPosition: rank should be a number from 1, got 0")},
+	{"h01", errors.New("NewPosition: rank should be a number from 1, got 01")},
+	{"A1", errors.New("NewPosition: first file character should be a-z, got A")},
+	{"i7", nil},
 	{"h7", nil},
 	{"c8", nil},
-	{"d9", errors.New("NewPosition: second rank character should be 1-8, got 9")},
+	{"d9", nil},
+	{"k12", nil},
+	{"p16", nil},
 }
 
 func TestNewPosition(t *testing.T) {
@@ -64,14 +70,15 @@ var moveTestCases = []struct {
 }
 
 func TestMove(t *testing.T) {
+	b := NewBoard()
 	for _, tc := range moveTestCases {
 		pos, err := NewPosition(tc.p)
 		if err != nil {
 			t.Errorf("Bad test case setup, start position %v returned err %v", tc.p, err)
 		}
-		got := pos.Move(tc.f, tc.r).String()
-		if got != tc.want {
-			t.Errorf("NewPosition(%v).Move(%v,%v).String() = %v, wanted %v", tc.p, tc.f, tc.r, got, tc.want)
+		got, ok := b.Step(*pos, tc.f, tc.r)
+		if !ok || got.String() != tc.want {
+			t.Errorf("NewBoard().Step(%v,%v,%v) = %v, %v, wanted %v", tc.p, tc.f, tc.r, got, ok, tc.want)
 		}
 	}
 }
@@ -0,0 +1,273 @@
+// Package randomizer provides the random devices that move pieces around:
+// dice, coins, spinners and arbitrary discrete distributions.  Each can be
+// sampled for simulation and also reports its exact probability mass
+// function for exact solvers.
+package randomizer
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Outcome is one possible value of a Distribution and its exact probability.
+type Outcome struct {
+	Value int
+	P     *big.Rat
+}
+
+// Distribution is a discrete probability distribution over integers.
+type Distribution interface {
+	// Sample draws a value using rng, or the global math/rand source if rng
+	// is nil.
+	Sample(rng *rand.Rand) int
+
+	// PMF returns the exact probability of every possible value, ordered by
+	// value.  Probabilities sum to one.
+	PMF() []Outcome
+}
+
+// intn draws a number in [0, n) from rng, or the global source if rng is nil.
+func intn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+	return rng.Intn(n)
+}
+
+// int63n draws a number in [0, n) from rng, or the global source if rng is
+// nil.
+func int63n(rng *rand.Rand, n int64) int64 {
+	if rng == nil {
+		return rand.Int63n(n)
+	}
+	return rng.Int63n(n)
+}
+
+// Dice rolls Count dice of Sides sides each and adds Modifier to the total.
+// With Count zero it always returns Modifier.
+type Dice struct {
+	Count, Sides, Modifier int
+}
+
+// dicePattern matches NdS dice notation with an optional modifier.
+var dicePattern = regexp.MustCompile(`^(\d*)d(\d+)([+-]\d+)?$`)
+
+// ParseDice reads dice notation such as "2d6", "d20" or "3d4-1", or a
+// constant such as "5" which always rolls the same value.
+func ParseDice(spec string) (*Dice, error) {
+	if n, err := strconv.Atoi(spec); err == nil {
+		return &Dice{Modifier: n}, nil
+	}
+	m := dicePattern.FindStringSubmatch(spec)
+	if m == nil {
+		return nil, fmt.Errorf("ParseDice: expected NdS+M notation, got %q", spec)
+	}
+	d := &Dice{Count: 1}
+	if m[1] != "" {
+		d.Count, _ = strconv.Atoi(m[1])
+	}
+	d.Sides, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		d.Modifier, _ = strconv.Atoi(m[3])
+	}
+	if d.Count < 1 || d.Sides < 1 {
+		return nil, fmt.Errorf("ParseDice: count and sides should be at least 1, got %q", spec)
+	}
+	return d, nil
+}
+
+// TwoD6 is the pair of six sided dice from the original problem.
+func TwoD6() *Dice {
+	return &Dice{Count: 2, Sides: 6}
+}
+
+func (d *Dice) Sample(rng *rand.Rand) int {
+	total := d.Modifier
+	for i := 0; i < d.Count; i++ {
+		// intn returns numbers from zero to Sides-1, so add one per die
+		total += intn(rng, d.Sides) + 1
+	}
+	return total
+}
+
+func (d *Dice) PMF() []Outcome {
+	// ways[i] counts the ways to roll a total of i over the dice so far.
+	ways := []*big.Int{big.NewInt(1)}
+	for i := 0; i < d.Count; i++ {
+		next := make([]*big.Int, len(ways)+d.Sides)
+		for j := range next {
+			next[j] = new(big.Int)
+		}
+		for total, w := range ways {
+			for face := 1; face <= d.Sides; face++ {
+				next[total+face].Add(next[total+face], w)
+			}
+		}
+		ways = next
+	}
+	denominator := new(big.Int).Exp(big.NewInt(int64(d.Sides)), big.NewInt(int64(d.Count)), nil)
+	out := make([]Outcome, 0)
+	for total, w := range ways {
+		if w.Sign() == 0 {
+			continue
+		}
+		out = append(out, Outcome{
+			Value: total + d.Modifier,
+			P:     new(big.Rat).SetFrac(w, denominator),
+		})
+	}
+	return out
+}
+
+func (d *Dice) String() string {
+	if d.Count == 0 {
+		return strconv.Itoa(d.Modifier)
+	}
+	if d.Modifier == 0 {
+		return fmt.Sprintf("%vd%v", d.Count, d.Sides)
+	}
+	return fmt.Sprintf("%vd%v%+d", d.Count, d.Sides, d.Modifier)
+}
+
+// Coin lands heads, sampled as 1, with probability PHeads and tails,
+// sampled as 0, otherwise.
+type Coin struct {
+	pHeads *big.Rat
+}
+
+// NewCoin builds a Coin landing heads with probability pHeads.  The
+// denominator of pHeads must fit in an int so the coin can be sampled exactly.
+func NewCoin(pHeads *big.Rat) (*Coin, error) {
+	if pHeads.Sign() < 0 || pHeads.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, fmt.Errorf("NewCoin: probability of heads should be 0-1, got %v", pHeads.RatString())
+	}
+	if !pHeads.Denom().IsInt64() || pHeads.Denom().Int64() > int64(int(^uint(0)>>1)) {
+		return nil, fmt.Errorf("NewCoin: denominator of %v is too large", pHeads.RatString())
+	}
+	return &Coin{pHeads: new(big.Rat).Set(pHeads)}, nil
+}
+
+// FairCoin returns a Coin landing heads half the time.
+func FairCoin() *Coin {
+	return &Coin{pHeads: big.NewRat(1, 2)}
+}
+
+// PHeads returns the probability this Coin lands heads.
+func (c *Coin) PHeads() *big.Rat {
+	return new(big.Rat).Set(c.pHeads)
+}
+
+// Toss returns true for heads and false for tails.
+func (c *Coin) Toss(rng *rand.Rand) bool {
+	return c.Sample(rng) == 1
+}
+
+func (c *Coin) Sample(rng *rand.Rand) int {
+	num := int(c.pHeads.Num().Int64())
+	denom := int(c.pHeads.Denom().Int64())
+	// Heads is the top num of denom equally likely draws, so a fair coin is
+	// heads exactly when rand.Intn(2) == 1.
+	if intn(rng, denom) >= denom-num {
+		return 1
+	}
+	return 0
+}
+
+func (c *Coin) PMF() []Outcome {
+	return []Outcome{
+		{Value: 0, P: new(big.Rat).Sub(big.NewRat(1, 1), c.pHeads)},
+		{Value: 1, P: c.PHeads()},
+	}
+}
+
+// Discrete draws each of its values with probability proportional to an
+// integer weight.
+type Discrete struct {
+	// values and weights are parallel, ordered by value.
+	values  []int
+	weights []int64
+	total   int64
+}
+
+// NewDiscrete builds a Discrete distribution from a weight per value.
+// Weights must not be negative and must not all be zero.
+func NewDiscrete(weights map[int]int64) (*Discrete, error) {
+	d := &Discrete{}
+	for v := range weights {
+		d.values = append(d.values, v)
+	}
+	sort.Ints(d.values)
+	for _, v := range d.values {
+		w := weights[v]
+		if w < 0 {
+			return nil, fmt.Errorf("NewDiscrete: weight of %v should not be negative, got %v", v, w)
+		}
+		d.weights = append(d.weights, w)
+		d.total += w
+	}
+	if d.total <= 0 {
+		return nil, fmt.Errorf("NewDiscrete: weights should not all be zero")
+	}
+	return d, nil
+}
+
+func (d *Discrete) Sample(rng *rand.Rand) int {
+	r := int63n(rng, d.total)
+	for i, w := range d.weights {
+		if r < w {
+			return d.values[i]
+		}
+		r -= w
+	}
+	// Unreachable, as r is less than the total of the weights.
+	panic(fmt.Sprintf("Discrete.Sample: %v exceeded total weight %v", r, d.total))
+}
+
+func (d *Discrete) PMF() []Outcome {
+	out := make([]Outcome, 0, len(d.values))
+	for i, v := range d.values {
+		if d.weights[i] == 0 {
+			continue
+		}
+		out = append(out, Outcome{Value: v, P: big.NewRat(d.weights[i], d.total)})
+	}
+	return out
+}
+
+// Spinner picks one of several sections, numbered from 0, with probability
+// proportional to each section's weight.  It generalizes a coin to choosing
+// among more than two directions.
+type Spinner struct {
+	Discrete
+}
+
+// NewSpinner builds a Spinner with one section per weight.
+func NewSpinner(weights ...int64) (*Spinner, error) {
+	m := make(map[int]int64)
+	for i, w := range weights {
+		m[i] = w
+	}
+	d, err := NewDiscrete(m)
+	if err != nil {
+		return nil, fmt.Errorf("NewSpinner: %w", err)
+	}
+	return &Spinner{*d}, nil
+}
+
+// FairSpinner builds a Spinner with n equally likely sections.
+func FairSpinner(n int) (*Spinner, error) {
+	weights := make([]int64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return NewSpinner(weights...)
+}
+
+// Sections returns the number of sections on this Spinner.
+func (s *Spinner) Sections() int {
+	return len(s.values)
+}
@@ -0,0 +1,169 @@
+package randomizer
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var parseDiceTestCases = []struct {
+	spec    string
+	want    *Dice
+	wantErr bool
+}{
+	{"2d6", &Dice{Count: 2, Sides: 6}, false},
+	{"d20", &Dice{Count: 1, Sides: 20}, false},
+	{"3d4-1", &Dice{Count: 3, Sides: 4, Modifier: -1}, false},
+	{"1d8+2", &Dice{Count: 1, Sides: 8, Modifier: 2}, false},
+	{"5", &Dice{Modifier: 5}, false},
+	{"0d6", nil, true},
+	{"2d0", nil, true},
+	{"2d", nil, true},
+	{"2x6", nil, true},
+	{"", nil, true},
+	{"2d6+", nil, true},
+}
+
+func TestParseDice(t *testing.T) {
+	for _, tc := range parseDiceTestCases {
+		got, err := ParseDice(tc.spec)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseDice(%q) returned err %v, wanted error %v", tc.spec, err, tc.wantErr)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseDice(%q) = %v, wanted %v", tc.spec, got, tc.want)
+		}
+		if got == nil {
+			continue
+		}
+		if again, err := ParseDice(got.String()); err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("ParseDice(%q).String() = %v does not parse back", tc.spec, got.String())
+		}
+	}
+}
+
+// assertPMF checks that d's PMF matches want, given as numerator and
+// denominator pairs per value.
+func assertPMF(t *testing.T, d Distribution, want map[int][2]int64) {
+	t.Helper()
+	got := d.PMF()
+	if len(got) != len(want) {
+		t.Errorf("%v PMF() = %v, wanted %v values", d, got, len(want))
+	}
+	total := new(big.Rat)
+	for i, o := range got {
+		if i > 0 && got[i-1].Value >= o.Value {
+			t.Errorf("%v PMF() is not ordered by value: %v", d, got)
+		}
+		w := want[o.Value]
+		if o.P.Cmp(big.NewRat(w[0], w[1])) != 0 {
+			t.Errorf("%v PMF()[%v] = %v, wanted %v/%v", d, o.Value, o.P, w[0], w[1])
+		}
+		total.Add(total, o.P)
+	}
+	if total.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("%v PMF() sums to %v, wanted 1", d, total)
+	}
+}
+
+func TestDicePMF(t *testing.T) {
+	assertPMF(t, TwoD6(), map[int][2]int64{
+		2: {1, 36}, 3: {2, 36}, 4: {3, 36}, 5: {4, 36}, 6: {5, 36}, 7: {6, 36},
+		8: {5, 36}, 9: {4, 36}, 10: {3, 36}, 11: {2, 36}, 12: {1, 36},
+	})
+	assertPMF(t, &Dice{Count: 1, Sides: 4, Modifier: -1}, map[int][2]int64{
+		0: {1, 4}, 1: {1, 4}, 2: {1, 4}, 3: {1, 4},
+	})
+	assertPMF(t, &Dice{Count: 3, Sides: 2}, map[int][2]int64{
+		3: {1, 8}, 4: {3, 8}, 5: {3, 8}, 6: {1, 8},
+	})
+	assertPMF(t, &Dice{Modifier: 7}, map[int][2]int64{7: {1, 1}})
+}
+
+func TestCoinPMF(t *testing.T) {
+	assertPMF(t, FairCoin(), map[int][2]int64{0: {1, 2}, 1: {1, 2}})
+	biased, err := NewCoin(big.NewRat(2, 3))
+	if err != nil {
+		t.Fatalf("NewCoin(2/3) returned err %v", err)
+	}
+	assertPMF(t, biased, map[int][2]int64{0: {1, 3}, 1: {2, 3}})
+	if _, err := NewCoin(big.NewRat(3, 2)); err == nil {
+		t.Errorf("NewCoin(3/2) returned nil err")
+	}
+	if _, err := NewCoin(big.NewRat(-1, 2)); err == nil {
+		t.Errorf("NewCoin(-1/2) returned nil err")
+	}
+}
+
+func TestDiscretePMF(t *testing.T) {
+	d, err := NewDiscrete(map[int]int64{-3: 1, 10: 3, 4: 0})
+	if err != nil {
+		t.Fatalf("NewDiscrete returned err %v", err)
+	}
+	assertPMF(t, d, map[int][2]int64{-3: {1, 4}, 10: {3, 4}})
+	if _, err := NewDiscrete(map[int]int64{1: 0}); err == nil {
+		t.Errorf("NewDiscrete with zero weights returned nil err")
+	}
+	if _, err := NewDiscrete(map[int]int64{1: -1, 2: 3}); err == nil {
+		t.Errorf("NewDiscrete with negative weight returned nil err")
+	}
+}
+
+func TestSpinnerPMF(t *testing.T) {
+	s, err := FairSpinner(3)
+	if err != nil {
+		t.Fatalf("FairSpinner(3) returned err %v", err)
+	}
+	if s.Sections() != 3 {
+		t.Errorf("Sections() = %v, wanted 3", s.Sections())
+	}
+	assertPMF(t, s, map[int][2]int64{0: {1, 3}, 1: {1, 3}, 2: {1, 3}})
+	weighted, err := NewSpinner(1, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpinner returned err %v", err)
+	}
+	assertPMF(t, weighted, map[int][2]int64{0: {1, 4}, 1: {2, 4}, 2: {1, 4}})
+}
+
+// TestSampleMatchesPMF draws many samples from each distribution and checks
+// every value seen is possible and frequencies are near the PMF.
+func TestSampleMatchesPMF(t *testing.T) {
+	biased, _ := NewCoin(big.NewRat(1, 5))
+	discrete, _ := NewDiscrete(map[int]int64{1: 1, 5: 2, 9: 7})
+	spinner, _ := NewSpinner(3, 1)
+	rng := rand.New(rand.NewSource(1))
+	const n = 100000
+	for _, d := range []Distribution{TwoD6(), &Dice{Count: 3, Sides: 4, Modifier: -2}, FairCoin(), biased, discrete, spinner} {
+		counts := make(map[int]int)
+		for i := 0; i < n; i++ {
+			counts[d.Sample(rng)]++
+		}
+		for _, o := range d.PMF() {
+			want, _ := o.P.Float64()
+			got := float64(counts[o.Value]) / n
+			if got < want-0.01 || got > want+0.01 {
+				t.Errorf("%v sampled %v with frequency %v, wanted about %v", d, o.Value, got, want)
+			}
+			delete(counts, o.Value)
+		}
+		if len(counts) != 0 {
+			t.Errorf("%v sampled impossible values %v", d, counts)
+		}
+	}
+}
+
+// TestFairSamplingMatchesMathRand confirms the fair coin and 2d6 consume a
+// random source exactly as rand.Intn did before this package existed.
+func TestFairSamplingMatchesMathRand(t *testing.T) {
+	a := rand.New(rand.NewSource(7))
+	b := rand.New(rand.NewSource(7))
+	for i := 0; i < 1000; i++ {
+		if got, want := FairCoin().Toss(a), b.Intn(2) == 1; got != want {
+			t.Fatalf("FairCoin().Toss() = %v on draw %v, wanted %v", got, i, want)
+		}
+		if got, want := TwoD6().Sample(a), b.Intn(6)+b.Intn(6)+2; got != want {
+			t.Fatalf("TwoD6().Sample() = %v on draw %v, wanted %v", got, i, want)
+		}
+	}
+}
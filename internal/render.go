@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderStyle selects how Render draws pieces.
+type RenderStyle int
+
+const (
+	// ASCII draws pieces as their FEN letters, upper case for White.
+	ASCII RenderStyle = iota
+	// UNICODE draws pieces as chess glyphs.
+	UNICODE
+)
+
+// ParseRenderStyle converts "ascii" or "unicode" into a RenderStyle.
+func ParseRenderStyle(name string) (RenderStyle, error) {
+	switch strings.ToLower(name) {
+	case "ascii":
+		return ASCII, nil
+	case "unicode":
+		return UNICODE, nil
+	}
+	return ASCII, fmt.Errorf("ParseRenderStyle: unknown style %q", name)
+}
+
+// RenderOptions controls what Render draws besides the pieces.
+type RenderOptions struct {
+	// Style selects letters or glyphs for the pieces.
+	Style RenderStyle
+
+	// MovesOf highlights the squares this piece can move to, if set.
+	MovesOf ChessPiece
+
+	// AttackedBy highlights the squares pieces of this Color attack, unless
+	// it is EMPTY.
+	AttackedBy Color
+}
+
+// glyphs maps each FEN letter to its Unicode chess glyph.
+var glyphs = map[rune]rune{
+	'K': '♔', 'Q': '♕', 'R': '♖', 'B': '♗', 'N': '♘', 'P': '♙',
+	'k': '♚', 'q': '♛', 'r': '♜', 'b': '♝', 'n': '♞', 'p': '♟',
+}
+
+// highlighted returns the squares movesOf can move to, if it is not nil,
+// along with the squares attacked by pieces of Color attackedBy, unless it is
+// EMPTY.
+func (b *Board) highlighted(movesOf ChessPiece, attackedBy Color) map[Position]bool {
+	highlight := make(map[Position]bool)
+	if movesOf != nil {
+		for _, p := range movesOf.LegalMoves() {
+			highlight[p] = true
+		}
+	}
+	if attackedBy != EMPTY {
+		for _, p := range b.AttackedSquares(attackedBy) {
+			highlight[p] = true
+		}
+	}
+	return highlight
+}
+
+// Render draws the board as text with the highest rank at the top, rank
+// numbers down the left and file letters along the bottom.  Empty squares
+// are dots and highlighted squares are bracketed.
+func (b *Board) Render(opts RenderOptions) string {
+	highlight := b.highlighted(opts.MovesOf, opts.AttackedBy)
+	labelWidth := len(fmt.Sprint(b.height))
+	lines := make([]string, 0, b.height+1)
+	var sb strings.Builder
+	for rank := b.height - 1; rank >= 0; rank-- {
+		fmt.Fprintf(&sb, "%*d ", labelWidth, rank+1)
+		for file := 0; file < b.width; file++ {
+			pos := Position{rank: rank, file: file}
+			square := '.'
+			if opts.Style == UNICODE {
+				square = '·'
+			}
+			if piece := b.positions[pos]; piece != nil {
+				square = fenLetter(piece)
+				if opts.Style == UNICODE {
+					square = glyphs[square]
+				}
+			}
+			if highlight[pos] {
+				fmt.Fprintf(&sb, "[%c]", square)
+			} else {
+				fmt.Fprintf(&sb, " %c ", square)
+			}
+		}
+		lines = append(lines, strings.TrimRight(sb.String(), " "))
+		sb.Reset()
+	}
+	sb.WriteString(strings.Repeat(" ", labelWidth+1))
+	for file := 0; file < b.width; file++ {
+		fmt.Fprintf(&sb, " %c ", 'a'+file)
+	}
+	lines = append(lines, strings.TrimRight(sb.String(), " "))
+	return strings.Join(lines, "\n") + "\n"
+}
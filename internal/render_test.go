@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	b, err := NewSizedBoard(4, 3, STANDARD)
+	if err != nil {
+		t.Fatalf("NewSizedBoard returned err %v", err)
+	}
+	rook := NewRook(BLACK)
+	mustPlace(t, b, rook, "a1")
+	mustPlace(t, b, NewKing(WHITE), "c1")
+	mustPlace(t, b, NewPawn(WHITE), "b3")
+
+	testCases := []struct {
+		opts RenderOptions
+		want string
+	}{
+		{
+			opts: RenderOptions{},
+			want: "3  .  P  .  .\n" +
+				"2  .  .  .  .\n" +
+				"1  r  .  K  .\n" +
+				"   a  b  c  d\n",
+		},
+		{
+			opts: RenderOptions{Style: UNICODE, MovesOf: rook},
+			want: "3 [·] ♙  ·  ·\n" +
+				"2 [·] ·  ·  ·\n" +
+				"1  ♜ [·][♔] ·\n" +
+				"   a  b  c  d\n",
+		},
+		{
+			// The King attacks the Rook's square too, and the Pawn on the top
+			// rank of a standard board attacks nothing.
+			opts: RenderOptions{AttackedBy: WHITE},
+			want: "3  .  P  .  .\n" +
+				"2  . [.][.][.]\n" +
+				"1  r [.] K [.]\n" +
+				"   a  b  c  d\n",
+		},
+	}
+	for _, tc := range testCases {
+		if got := b.Render(tc.opts); got != tc.want {
+			t.Errorf("Render(%+v) =\n%v\nwanted\n%v", tc.opts, got, tc.want)
+		}
+	}
+}
+
+func TestRenderWideLabels(t *testing.T) {
+	b, err := NewSizedBoard(2, 10, TORUS)
+	if err != nil {
+		t.Fatalf("NewSizedBoard returned err %v", err)
+	}
+	got := b.Render(RenderOptions{})
+	want := "10  .  .\n"
+	if got[:len(want)] != want {
+		t.Errorf("Render() began %q, wanted %q", got[:len(want)], want)
+	}
+}
+
+func TestParseRenderStyle(t *testing.T) {
+	testCases := []struct {
+		name    string
+		want    RenderStyle
+		wantErr bool
+	}{
+		{"ascii", ASCII, false},
+		{"Unicode", UNICODE, false},
+		{"braille", ASCII, true},
+	}
+	for _, tc := range testCases {
+		got, err := ParseRenderStyle(tc.name)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("ParseRenderStyle(%q) = %v, %v, wanted %v, error %v", tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode"
+)
+
+// sanPattern matches Standard Algebraic Notation: an optional piece letter,
+// an optional file and rank telling apart pieces that could both move, an
+// optional capture mark, the destination square and an optional check mark.
+var sanPattern = regexp.MustCompile(`^([KQRBN])?([a-z])?([1-9][0-9]*)?(x)?([a-z][1-9][0-9]*)[+#]?$`)
+
+// ParseSAN finds the legal move of the side to move described by san in
+// Standard Algebraic Notation, such as "Rh3", "Nbd2", "exd5" or "e4".  Pawns
+// do not promote and Kings do not castle, so neither is accepted.
+func (b *Board) ParseSAN(san string) (Move, error) {
+	m := sanPattern.FindStringSubmatch(san)
+	if m == nil {
+		return Move{}, fmt.Errorf("ParseSAN: cannot parse %q", san)
+	}
+	letter := 'P'
+	if m[1] != "" {
+		letter = rune(m[1][0])
+	}
+	dest, err := NewPosition(m[5])
+	if err != nil {
+		return Move{}, err
+	}
+	fromFile, fromRank := -1, -1
+	if m[2] != "" {
+		fromFile = int(m[2][0] - 'a')
+	}
+	if m[3] != "" {
+		r, _ := strconv.Atoi(m[3])
+		fromRank = r - 1
+	}
+	matches := make([]Move, 0)
+	for _, move := range b.AllLegalMoves(b.sideToMove) {
+		if unicode.ToUpper(fenLetter(move.Piece)) != letter || move.To != *dest {
+			continue
+		}
+		if (fromFile >= 0 && move.From.file != fromFile) || (fromRank >= 0 && move.From.rank != fromRank) {
+			continue
+		}
+		matches = append(matches, move)
+	}
+	switch len(matches) {
+	case 0:
+		return Move{}, fmt.Errorf("ParseSAN: %v has no legal move %v", b.sideToMove, san)
+	case 1:
+		return matches[0], nil
+	}
+	return Move{}, fmt.Errorf("ParseSAN: %v is ambiguous between %v", san, matches)
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestParseSAN(t *testing.T) {
+	b := NewBoardWithTopology(STANDARD)
+	if err := b.FromFEN("4k3/8/8/3p4/4P3/8/4K3/R6R w - - 0 1"); err != nil {
+		t.Fatalf("FromFEN returned err %v", err)
+	}
+	testCases := []struct {
+		san      string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{"e5", "e4", "e5", false},
+		{"exd5", "e4", "d5", false},
+		{"Kf2", "e2", "f2", false},
+		{"Ra2", "a1", "a2", false},
+		{"Rhf1+", "h1", "f1", false},
+		{"Rad1", "a1", "d1", false},
+		{"R1d1", "", "", true},
+		{"Rd1", "", "", true},
+		{"Ra8", "a1", "a8", false},
+		{"Nf3", "", "", true},
+		{"e6", "", "", true},
+		{"O-O", "", "", true},
+		{"Zz9", "", "", true},
+	}
+	for _, tc := range testCases {
+		got, err := b.ParseSAN(tc.san)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseSAN(%v) = %v, wanted an error", tc.san, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSAN(%v) returned err %v", tc.san, err)
+			continue
+		}
+		if got.From != mustPosition(t, tc.wantFrom) || got.To != mustPosition(t, tc.wantTo) {
+			t.Errorf("ParseSAN(%v) = %v, wanted %v-%v", tc.san, got, tc.wantFrom, tc.wantTo)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Arrow marks a move on an SVG diagram.  It is a displacement from From
+// rather than a destination, so a move wrapping around an edge can be drawn
+// leaving that edge and re-entering on the opposite side.
+type Arrow struct {
+	From Position
+
+	// Files and Ranks count the squares moved right and up.
+	Files, Ranks int
+}
+
+// SVGOptions controls what RenderSVG draws besides the squares and pieces.
+type SVGOptions struct {
+	// SquareSize is the width of each square in pixels, 40 if zero.
+	SquareSize int
+
+	// Arrows are drawn over the pieces in order.
+	Arrows []Arrow
+
+	// Heat shades each square it holds from white at 0 to red at 1 in
+	// place of the usual light or dark color.
+	Heat map[Position]float64
+
+	// Labels writes a short note along the bottom of each square it holds.
+	Labels map[Position]string
+}
+
+// heatColor returns the color shading a square with heat h, clamped to the
+// range 0 to 1.
+func heatColor(h float64) string {
+	h = math.Max(0, math.Min(1, h))
+	fade := int(math.Round(255 * (1 - h)))
+	return fmt.Sprintf("#ff%02x%02x", fade, fade)
+}
+
+const (
+	svgLightSquare = "#f0d9b5"
+	svgDarkSquare  = "#b58863"
+	svgArrow       = "#15781b"
+)
+
+// arrowSegments splits a into the line segments drawn on the board, in units
+// of squares from the bottom left corner.  Each time the line crosses an edge
+// that wraps, it continues from the opposite edge.
+func (b *Board) arrowSegments(a Arrow) [][4]float64 {
+	x0, y0 := float64(a.From.file)+0.5, float64(a.From.rank)+0.5
+	dx, dy := float64(a.Files), float64(a.Ranks)
+	w, h := float64(b.width), float64(b.height)
+	cuts := []float64{0, 1}
+	// crossings adds the fraction of the way along the arrow at which it
+	// crosses each multiple of size, starting from start and moving d.
+	crossings := func(start, d, size float64) {
+		if d == 0 {
+			return
+		}
+		lo, hi := math.Min(start, start+d), math.Max(start, start+d)
+		for k := math.Ceil(lo / size); k*size < hi; k++ {
+			if t := (k*size - start) / d; t > 0 && t < 1 {
+				cuts = append(cuts, t)
+			}
+		}
+	}
+	if b.topology.wrapsFiles() {
+		crossings(x0, dx, w)
+	}
+	if b.topology.wrapsRanks() {
+		crossings(y0, dy, h)
+	}
+	sort.Float64s(cuts)
+	segments := make([][4]float64, 0, len(cuts)-1)
+	for i := 1; i < len(cuts); i++ {
+		t1, t2 := cuts[i-1], cuts[i]
+		if t2-t1 < 1e-9 {
+			continue
+		}
+		// Shift each segment back onto the board by the copies of the board
+		// its midpoint lies in.
+		var ox, oy float64
+		mid := (t1 + t2) / 2
+		if b.topology.wrapsFiles() {
+			ox = math.Floor((x0+mid*dx)/w) * w
+		}
+		if b.topology.wrapsRanks() {
+			oy = math.Floor((y0+mid*dy)/h) * h
+		}
+		segments = append(segments, [4]float64{
+			x0 + t1*dx - ox, y0 + t1*dy - oy,
+			x0 + t2*dx - ox, y0 + t2*dy - oy,
+		})
+	}
+	return segments
+}
+
+// RenderSVG draws the board as a standalone SVG image with the highest rank
+// at the top, rank numbers down the left and file letters along the bottom.
+// Pieces are drawn as Unicode chess glyphs.
+func (b *Board) RenderSVG(opts SVGOptions) string {
+	size := float64(opts.SquareSize)
+	if size <= 0 {
+		size = 40
+	}
+	margin := size / 2
+	// px and py convert board units from the bottom left corner to pixels.
+	px := func(x float64) float64 { return margin + x*size }
+	py := func(y float64) float64 { return margin + (float64(b.height)-y)*size }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g">`+"\n",
+		px(float64(b.width))+margin, py(0)+margin, px(float64(b.width))+margin, py(0)+margin)
+	fmt.Fprintf(&sb, `<defs><marker id="arrowhead" viewBox="0 0 10 10" refX="5" refY="5" markerWidth="3" markerHeight="3" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="%v"/></marker></defs>`+"\n", svgArrow)
+	for rank := 0; rank < b.height; rank++ {
+		for file := 0; file < b.width; file++ {
+			fill := svgLightSquare
+			if (rank+file)%2 == 0 {
+				fill = svgDarkSquare
+			}
+			if h, ok := opts.Heat[Position{rank: rank, file: file}]; ok {
+				fill = heatColor(h)
+			}
+			fmt.Fprintf(&sb, `<rect x="%g" y="%g" width="%g" height="%g" fill="%v"/>`+"\n",
+				px(float64(file)), py(float64(rank+1)), size, size, fill)
+		}
+	}
+	font := fmt.Sprintf(`font-family="sans-serif" font-size="%g" text-anchor="middle" dominant-baseline="central"`, size*0.3)
+	for rank := 0; rank < b.height; rank++ {
+		fmt.Fprintf(&sb, `<text x="%g" y="%g" %v>%v</text>`+"\n", margin/2, py(float64(rank)+0.5), font, rank+1)
+	}
+	for file := 0; file < b.width; file++ {
+		fmt.Fprintf(&sb, `<text x="%g" y="%g" %v>%c</text>`+"\n", px(float64(file)+0.5), py(0)+margin/2, font, 'a'+file)
+	}
+	for _, sq := range b.Squares() {
+		piece := b.positions[sq]
+		if piece == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, `<text x="%g" y="%g" font-size="%g" text-anchor="middle" dominant-baseline="central">%c</text>`+"\n",
+			px(float64(sq.file)+0.5), py(float64(sq.rank)+0.5), size*0.8, glyphs[fenLetter(piece)])
+	}
+	for _, sq := range b.Squares() {
+		label, ok := opts.Labels[sq]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&sb, `<text x="%g" y="%g" font-family="sans-serif" font-size="%g" text-anchor="middle">%v</text>`+"\n",
+			px(float64(sq.file)+0.5), py(float64(sq.rank)+0.1), size*0.25, html.EscapeString(label))
+	}
+	for _, a := range opts.Arrows {
+		if a.Files == 0 && a.Ranks == 0 {
+			continue
+		}
+		segments := b.arrowSegments(a)
+		for i, s := range segments {
+			marker := ""
+			if i == len(segments)-1 {
+				marker = ` marker-end="url(#arrowhead)"`
+			}
+			fmt.Fprintf(&sb, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%v" stroke-width="%g" stroke-opacity="0.8"%v/>`+"\n",
+				px(s[0]), py(s[1]), px(s[2]), py(s[3]), svgArrow, size/8, marker)
+		}
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
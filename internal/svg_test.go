@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArrowSegments(t *testing.T) {
+	testCases := []struct {
+		topology Topology
+		arrow    Arrow
+		want     [][4]float64
+	}{
+		{
+			topology: TORUS,
+			arrow:    Arrow{From: Position{rank: 0, file: 3}, Files: 2},
+			want:     [][4]float64{{3.5, 0.5, 5.5, 0.5}},
+		},
+		{
+			// h1 right 3 leaves the right edge and re-enters on the left.
+			topology: TORUS,
+			arrow:    Arrow{From: Position{rank: 0, file: 7}, Files: 3},
+			want:     [][4]float64{{7.5, 0.5, 8, 0.5}, {0, 0.5, 2.5, 0.5}},
+		},
+		{
+			// a1 down 9 wraps past the bottom edge once and lands on a8.
+			topology: TORUS,
+			arrow:    Arrow{From: Position{rank: 0, file: 0}, Ranks: -9},
+			want:     [][4]float64{{0.5, 0.5, 0.5, 0}, {0.5, 8, 0.5, 0}, {0.5, 8, 0.5, 7.5}},
+		},
+		{
+			// h8 up and right through the corner reappears at a1.
+			topology: TORUS,
+			arrow:    Arrow{From: Position{rank: 7, file: 7}, Files: 1, Ranks: 1},
+			want:     [][4]float64{{7.5, 7.5, 8, 8}, {0, 0, 0.5, 0.5}},
+		},
+		{
+			// Only files wrap on a horizontal cylinder.
+			topology: HORIZONTAL_CYLINDER,
+			arrow:    Arrow{From: Position{rank: 7, file: 7}, Files: 1, Ranks: 1},
+			want:     [][4]float64{{7.5, 7.5, 8, 8}, {0, 8, 0.5, 8.5}},
+		},
+	}
+	for _, tc := range testCases {
+		b := NewBoardWithTopology(tc.topology)
+		if got := b.arrowSegments(tc.arrow); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("arrowSegments(%+v) on %v = %v, wanted %v", tc.arrow, tc.topology, got, tc.want)
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	b := NewBoard()
+	mustPlace(t, b, NewRook(BLACK), "c1")
+	mustPlace(t, b, NewBishop(WHITE), "c3")
+	got := b.RenderSVG(SVGOptions{Arrows: []Arrow{
+		{From: mustPosition(t, "h1"), Files: 3},
+		{From: mustPosition(t, "c1")},
+	}})
+
+	counts := make(map[string]int)
+	d := xml.NewDecoder(strings.NewReader(got))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			counts[se.Name.Local]++
+		}
+	}
+	// 8 rank labels, 8 file labels and 2 pieces; the wrapped arrow takes two
+	// lines and the empty one none.
+	want := map[string]int{"svg": 1, "defs": 1, "marker": 1, "path": 1, "rect": 64, "text": 18, "line": 2}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("RenderSVG drew elements %v, wanted %v", counts, want)
+	}
+	if !strings.Contains(got, "♜") || !strings.Contains(got, "♗") {
+		t.Errorf("RenderSVG did not draw both pieces:\n%v", got)
+	}
+	if strings.Count(got, "marker-end") != 1 {
+		t.Errorf("RenderSVG should put one arrowhead on the last segment:\n%v", got)
+	}
+}
+
+func TestRenderSVGHeat(t *testing.T) {
+	b := NewBoard()
+	a1, h8 := mustPosition(t, "a1"), mustPosition(t, "h8")
+	got := b.RenderSVG(SVGOptions{
+		Heat:   map[Position]float64{a1: 1, h8: 0},
+		Labels: map[Position]string{a1: "100%", h8: "<1%"},
+	})
+	for _, want := range []string{`fill="#ff0000"`, `fill="#ffffff"`, ">100%<", ">&lt;1%<"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSVG did not draw %v:\n%v", want, got)
+		}
+	}
+	if err := xml.Unmarshal([]byte(got), new(struct{})); err != nil {
+		t.Errorf("RenderSVG drew invalid XML: %v", err)
+	}
+}
+
+func TestHeatColor(t *testing.T) {
+	testCases := []struct {
+		h    float64
+		want string
+	}{
+		{0, "#ffffff"},
+		{0.5, "#ff8080"},
+		{1, "#ff0000"},
+		{-1, "#ffffff"},
+		{2, "#ff0000"},
+	}
+	for _, tc := range testCases {
+		if got := heatColor(tc.h); got != tc.want {
+			t.Errorf("heatColor(%v) = %v, wanted %v", tc.h, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// Topology describes which edges of a Board wrap around to the opposite
+// edge.  Pieces consult the Board's Topology whenever they step across it.
+type Topology int
+
+const (
+	// STANDARD is a normal chess board.  No edges wrap.
+	STANDARD Topology = iota
+	// HORIZONTAL_CYLINDER wraps the left and right edges, so files wrap
+	// but ranks do not.
+	HORIZONTAL_CYLINDER
+	// VERTICAL_CYLINDER wraps the top and bottom edges, so ranks wrap
+	// but files do not.
+	VERTICAL_CYLINDER
+	// TORUS wraps every edge.  This is the board of the original problem.
+	TORUS
+)
+
+// wrapsFiles returns true if stepping off the left or right edge re-enters
+// on the opposite edge.
+func (t Topology) wrapsFiles() bool {
+	return t == HORIZONTAL_CYLINDER || t == TORUS
+}
+
+// wrapsRanks returns true if stepping off the top or bottom edge re-enters
+// on the opposite edge.
+func (t Topology) wrapsRanks() bool {
+	return t == VERTICAL_CYLINDER || t == TORUS
+}
+
+// wrap folds v back into the range [0, size) if wraps is set.  Returns false
+// if v is out of range and cannot wrap.
+func wrap(v, size int, wraps bool) (int, bool) {
+	if v >= 0 && v < size {
+		return v, true
+	}
+	if !wraps {
+		return v, false
+	}
+	v = v % size
+	if v < 0 {
+		v = v + size
+	}
+	return v, true
+}
+
+// step computes the position f files and r ranks away from p on a board
+// width files wide and height ranks tall with this Topology.  Returns false
+// if the step leaves the board.
+func (t Topology) step(p Position, f, r, width, height int) (Position, bool) {
+	file, ok := wrap(p.file+f, width, t.wrapsFiles())
+	if !ok {
+		return p, false
+	}
+	rank, ok := wrap(p.rank+r, height, t.wrapsRanks())
+	if !ok {
+		return p, false
+	}
+	return Position{
+		rank: rank,
+		file: file,
+	}, true
+}
+
+func (t Topology) String() string {
+	switch t {
+	case STANDARD:
+		return "standard"
+	case HORIZONTAL_CYLINDER:
+		return "horizontal cylinder"
+	case VERTICAL_CYLINDER:
+		return "vertical cylinder"
+	case TORUS:
+		return "torus"
+	}
+	return "unknown"
+}
+
+// ParseTopology converts the name printed by Topology.String back into a
+// Topology.
+func ParseTopology(name string) (Topology, error) {
+	for _, t := range []Topology{STANDARD, HORIZONTAL_CYLINDER, VERTICAL_CYLINDER, TORUS} {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return STANDARD, fmt.Errorf("ParseTopology: unknown topology %q", name)
+}
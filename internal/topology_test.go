@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"testing"
+)
+
+var stepTestCases = []struct {
+	topology Topology
+	p        string
+	f        int
+	r        int
+	want     string
+	wantOk   bool
+}{
+	{STANDARD, "a1", 1, 1, "b2", true},
+	{STANDARD, "a1", -1, 0, "", false},
+	{STANDARD, "a1", 0, -1, "", false},
+	{STANDARD, "h8", 1, 0, "", false},
+	{STANDARD, "h8", 0, 1, "", false},
+	{STANDARD, "c4", 2, -1, "e3", true},
+	{HORIZONTAL_CYLINDER, "a1", -1, 0, "h1", true},
+	{HORIZONTAL_CYLINDER, "h8", 1, 0, "a8", true},
+	{HORIZONTAL_CYLINDER, "a1", 0, -1, "", false},
+	{HORIZONTAL_CYLINDER, "h8", 1, 1, "", false},
+	{VERTICAL_CYLINDER, "a1", 0, -1, "a8", true},
+	{VERTICAL_CYLINDER, "h8", 0, 1, "h1", true},
+	{VERTICAL_CYLINDER, "a1", -1, 0, "", false},
+	{VERTICAL_CYLINDER, "h8", 1, 1, "", false},
+	{TORUS, "a1", -1, -1, "h8", true},
+	{TORUS, "h8", 1, 1, "a1", true},
+	{TORUS, "a1", 8, 8, "a1", true},
+	{TORUS, "a1", -8, -8, "a1", true},
+}
+
+func TestStep(t *testing.T) {
+	for _, tc := range stepTestCases {
+		b := NewBoardWithTopology(tc.topology)
+		got, ok := b.Step(mustPosition(t, tc.p), tc.f, tc.r)
+		if ok != tc.wantOk {
+			t.Errorf("%v Step(%v,%v,%v) returned ok %v, wanted %v", tc.topology, tc.p, tc.f, tc.r, ok, tc.wantOk)
+			continue
+		}
+		if ok && got.String() != tc.want {
+			t.Errorf("%v Step(%v,%v,%v) = %v, wanted %v", tc.topology, tc.p, tc.f, tc.r, got, tc.want)
+		}
+	}
+}
+
+var topologyMovementTestCases = []struct {
+	topology       Topology
+	newPiece       func(c Color) ChessPiece
+	start          string
+	dest           string
+	blackPositions []string
+	want           bool
+}{
+	{STANDARD, rookPiece, "b2", "h2", []string{}, true},
+	{STANDARD, rookPiece, "b2", "h2", []string{"c2"}, false},
+	{HORIZONTAL_CYLINDER, rookPiece, "b2", "h2", []string{"c2"}, true},
+	{VERTICAL_CYLINDER, rookPiece, "b2", "h2", []string{"c2"}, false},
+	{STANDARD, rookPiece, "f5", "f8", []string{"f7"}, false},
+	{HORIZONTAL_CYLINDER, rookPiece, "f5", "f8", []string{"f7"}, false},
+	{VERTICAL_CYLINDER, rookPiece, "f5", "f8", []string{"f7"}, true},
+	{STANDARD, bishopPiece, "b2", "d8", []string{}, false},
+	{HORIZONTAL_CYLINDER, bishopPiece, "b2", "d8", []string{}, true},
+	{VERTICAL_CYLINDER, bishopPiece, "b2", "d8", []string{}, true},
+	{TORUS, bishopPiece, "b2", "d8", []string{}, true},
+	{HORIZONTAL_CYLINDER, bishopPiece, "b2", "h4", []string{}, true},
+	{STANDARD, bishopPiece, "b2", "h4", []string{}, false},
+	{VERTICAL_CYLINDER, bishopPiece, "b2", "c1", []string{}, true},
+	{VERTICAL_CYLINDER, bishopPiece, "b2", "d8", []string{"c1"}, false},
+	{STANDARD, knightPiece, "a1", "h3", []string{}, false},
+	{HORIZONTAL_CYLINDER, knightPiece, "a1", "h3", []string{}, true},
+	{STANDARD, queenPiece, "a1", "h8", []string{}, true},
+	{STANDARD, queenPiece, "a1", "h8", []string{"d4"}, false},
+	{TORUS, queenPiece, "a1", "h8", []string{"d4"}, true},
+	{STANDARD, kingPiece, "a1", "h8", []string{}, false},
+	{TORUS, kingPiece, "a1", "h8", []string{}, true},
+	{STANDARD, pawnPiece, "h8", "h1", []string{}, false},
+	{VERTICAL_CYLINDER, pawnPiece, "h8", "h1", []string{}, true},
+	{VERTICAL_CYLINDER, pawnPiece, "h4", "a5", []string{"a5"}, false},
+}
+
+func rookPiece(c Color) ChessPiece   { return NewRook(c) }
+func bishopPiece(c Color) ChessPiece { return NewBishop(c) }
+func knightPiece(c Color) ChessPiece { return NewKnight(c) }
+func queenPiece(c Color) ChessPiece  { return NewQueen(c) }
+func kingPiece(c Color) ChessPiece   { return NewKing(c) }
+func pawnPiece(c Color) ChessPiece   { return NewPawn(c) }
+
+func TestTopologyMovement(t *testing.T) {
+	for _, tc := range topologyMovementTestCases {
+		p := tc.newPiece(WHITE)
+		b := NewBoardWithTopology(tc.topology)
+		mustPlace(t, b, p, tc.start)
+
+		for _, pos := range tc.blackPositions {
+			mustPlace(t, b, NewRook(BLACK), pos)
+		}
+		got := p.IsLegalMove(mustPosition(t, tc.dest))
+		if got != tc.want {
+			t.Errorf("%v %v to %v with black %v = %v, wanted %v", tc.topology, p, tc.dest, tc.blackPositions, got, tc.want)
+		}
+	}
+}
+
+func TestParseTopology(t *testing.T) {
+	for _, want := range []Topology{STANDARD, HORIZONTAL_CYLINDER, VERTICAL_CYLINDER, TORUS} {
+		got, err := ParseTopology(want.String())
+		if err != nil || got != want {
+			t.Errorf("ParseTopology(%q) = %v, %v, wanted %v", want.String(), got, err, want)
+		}
+	}
+	if _, err := ParseTopology("klein bottle"); err == nil {
+		t.Errorf("ParseTopology(\"klein bottle\") returned nil err")
+	}
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/rand"
+	"os"
+	"time"
 
 	"github.com/Techbert08/ChessProblem/internal"
+	"github.com/Techbert08/ChessProblem/internal/randomizer"
 )
 
 // coin is an interface for a random (or not) coin
@@ -18,7 +22,7 @@ type coin interface {
 type realCoin struct{}
 
 func (r realCoin) Toss() bool {
-	return rand.Intn(2) == 1
+	return randomizer.FairCoin().Toss(nil)
 }
 
 // twoDice is an interface for a random (or not) set of dice.
@@ -31,53 +35,96 @@ type twoDice interface {
 type realDice struct{}
 
 func (d realDice) Roll() int {
-	// Intn returns numbers from zero to 5, so add one per die
-	return rand.Intn(6) + rand.Intn(6) + 2
+	return randomizer.TwoD6().Sample(nil)
 }
 
-// evaluateProblem runs the stated problem, emitting log statements to the
-// returned slice of strings.  On error the program terminates, but logs emitted
-// so far are in the output slice.
-func evaluateProblem(c coin, d twoDice, numMoves int) ([]string, error) {
-	out := make([]string, 0)
-	board := internal.NewBoard()
-	rook := internal.NewRook(internal.BLACK)
-	if err := board.PlacePiece(rook, "h1"); err != nil {
-		return out, err
+// moveRook moves rook f files and r ranks, wrapping around the board's edges.
+// Returns the events describing the move.
+func moveRook(game *internal.Game, rook *internal.Rook, turn, f, r int) ([]event, error) {
+	from := *rook.GetPosition()
+	dest, ok := game.Board().Step(from, f, r)
+	if !ok {
+		return nil, fmt.Errorf("%v cannot move off the board", rook)
 	}
-	bishop := internal.NewBishop(internal.WHITE)
-	if err := board.PlacePiece(bishop, "c3"); err != nil {
-		return out, err
+	if err := game.Move(rook, dest); err != nil {
+		return nil, err
 	}
-	for i := 0; i < numMoves; i++ {
-		roll := d.Roll()
-		if c.Toss() {
-			out = append(out, fmt.Sprintf("Heads, rolled %v", roll))
-			if err := board.MovePiece(rook, rook.GetPosition().Move(0, roll)); err != nil {
-				return out, err
-			}
-		} else {
-			out = append(out, fmt.Sprintf("Tails, rolled %v", roll))
-			if err := board.MovePiece(rook, rook.GetPosition().Move(roll, 0)); err != nil {
-				return out, err
+	m, _ := game.Board().LastMove()
+	return moveEvents(turn, rook, from, dest, m.Captured), nil
+}
+
+// evaluateProblem runs the stated problem for numMoves turns, emitting events
+// to the returned slice.  See problem.play.
+func evaluateProblem(c coin, d twoDice, numMoves int) ([]event, error) {
+	p := defaultProblem()
+	p.moves = numMoves
+	return p.play(c, d)
+}
+
+// subcommands maps each subcommand name to the function running it with the
+// remaining command line arguments.
+var subcommands = map[string]func(args []string) error{
+	"simulate": runSimulate,
+	"exact":    runExact,
+	"replay":   runReplay,
+	"play":     runPlay,
+	"heatmap":  runHeatmap,
+	"strategy": runStrategy,
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Println("Terminated with error: ", err)
+				os.Exit(1)
 			}
+			return
 		}
-		out = append(out, fmt.Sprint(rook))
-		if bishop.GetPosition() == nil {
-			return append(out, "Rook takes bishop, Black wins"), nil
-		}
-		// Rook should never be nil, panic is fine if it is.
-		if bishop.IsLegalMove(*rook.GetPosition()) {
-			return append(out, "Bishop can take rook, White wins"), nil
+	}
+	scenarioPath := flag.String("scenario", "", "JSON scenario file to play instead of the original problem")
+	format := flag.String("format", "text", "output format: text, json or csv")
+	seed := flag.Int64("seed", 0, "seed for the coin and dice, or 0 to pick one from the clock")
+	svgPath := flag.String("svg", "", "file to draw the final position and the Rook's moves in as SVG")
+	gifPath := flag.String("gif", "", "file to write an animated GIF of the game to")
+	p := defaultProblem()
+	p.addFlags(flag.CommandLine)
+	p.addBoardFlag(flag.CommandLine)
+	flag.Parse()
+	if err := checkFormat(*format); err != nil {
+		fmt.Println("Terminated with error: ", err)
+		os.Exit(1)
+	}
+	if err := p.validate(); err != nil {
+		fmt.Println("Terminated with error: ", err)
+		os.Exit(1)
+	}
+	if (*svgPath != "" || *gifPath != "") && *scenarioPath != "" {
+		fmt.Println("Terminated with error: ", "-svg and -gif only draw the original problem, not scenarios")
+		os.Exit(1)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	var events []event
+	var err error
+	if *scenarioPath == "" {
+		events, err = p.play(&seededCoin{rng: rng}, &seededDice{rng: rng})
+	} else {
+		var s *scenario
+		if s, err = loadScenario(*scenarioPath); err == nil {
+			events, err = s.run(rngDrawer{rng: rng})
 		}
 	}
-	return append(out, "Rook escapes, Black wins"), nil
-}
-
-func main() {
-	logs, err := evaluateProblem(&realCoin{}, &realDice{}, 15)
-	for _, l := range logs {
-		fmt.Println(l)
+	if writeErr := writeEvents(os.Stdout, *format, *seed, events); writeErr != nil && err == nil {
+		err = writeErr
+	}
+	if *svgPath != "" && err == nil {
+		err = writeGameSVG(*svgPath, p, events)
+	}
+	if *gifPath != "" && err == nil {
+		err = writeGameGIF(*gifPath, p, events)
 	}
 	if err != nil {
 		fmt.Println("Terminated with error: ", err)
@@ -6,32 +6,6 @@ import (
 	"testing"
 )
 
-// loadedCoin is a fake coin
-type loadedCoin struct {
-	// Outcome is the list of flips that will be returned.
-	// Flipping more times than length of Outcome slice will panic.
-	Outcome []bool
-}
-
-func (l *loadedCoin) Toss() bool {
-	out, remainder := l.Outcome[0], l.Outcome[1:]
-	l.Outcome = remainder
-	return out
-}
-
-// loadedDice is a fake set of dice
-type loadedDice struct {
-	// Outcome is the list of rolls that will be returned.
-	// Rolling more times than length of Outcome slice will panic.
-	Outcome []int
-}
-
-func (l *loadedDice) Roll() int {
-	out, remainder := l.Outcome[0], l.Outcome[1:]
-	l.Outcome = remainder
-	return out
-}
-
 var testCases = []struct {
 	coin     []bool
 	dice     []int
@@ -64,21 +38,22 @@ var testCases = []struct {
 
 func TestEvaluateProblemDeterministic(t *testing.T) {
 	for _, tc := range testCases {
-		got, err := evaluateProblem(&loadedCoin{Outcome: tc.coin}, &loadedDice{Outcome: tc.dice}, tc.numTurns)
+		events, err := evaluateProblem(&loadedCoin{Outcome: tc.coin}, &loadedDice{Outcome: tc.dice}, tc.numTurns)
 		if err != nil {
 			t.Errorf("evaluateProblem(%v,%v,1) returned err: %v", tc.coin, tc.dice, err)
 		}
-		if !reflect.DeepEqual(got, tc.want) {
+		if got := renderText(events); !reflect.DeepEqual(got, tc.want) {
 			t.Errorf("evaluateProblem(%v,%v,1) = %v, wanted %v", tc.coin, tc.dice, got, tc.want)
 		}
 	}
 }
 
 func TestEvaluateProblemCompletes(t *testing.T) {
-	got, err := evaluateProblem(&realCoin{}, &realDice{}, 15)
+	events, err := evaluateProblem(&realCoin{}, &realDice{}, 15)
 	if err != nil {
 		t.Errorf("evaluateProblem with random inputs returned error %v", err)
 	}
+	got := renderText(events)
 	if !strings.Contains(got[len(got)-1], "wins") {
 		t.Errorf("evaluateProblem did not terminate with a winner, messages were %v", got)
 	}
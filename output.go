@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// formats lists the output formats accepted by the -format flags.
+var formats = []string{"text", "json", "csv"}
+
+// checkFormat returns an error if format is not one of formats.
+func checkFormat(format string) error {
+	for _, f := range formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("format should be one of %v, got %q", formats, format)
+}
+
+// winnerName returns the name of winner, or an empty string if nobody won.
+func winnerName(winner internal.Color) string {
+	if winner == internal.EMPTY {
+		return ""
+	}
+	return winner.String()
+}
+
+// eventRecord is the flat form of an event written as JSON or CSV.  Fields
+// that do not apply to an event's type are left empty.
+type eventRecord struct {
+	Type      string `json:"type"`
+	Turn      int    `json:"turn"`
+	Roll      *int   `json:"roll,omitempty"`
+	Heads     *bool  `json:"heads,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Piece     string `json:"piece,omitempty"`
+	By        string `json:"by,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Square    string `json:"square,omitempty"`
+	Winner    string `json:"winner,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Diagram   string `json:"diagram,omitempty"`
+}
+
+// eventRecordHeader names the CSV columns written for each eventRecord,
+// after the seed of the game.
+var eventRecordHeader = []string{"seed", "type", "turn", "roll", "heads", "direction", "piece", "by", "from", "to", "square", "winner", "reason", "diagram"}
+
+// newEventRecord flattens e into an eventRecord.
+func newEventRecord(e event) eventRecord {
+	r := eventRecord{Type: e.eventType()}
+	switch e := e.(type) {
+	case diceRolled:
+		roll := e.Roll
+		r.Turn, r.Roll = e.Turn, &roll
+	case coinTossed:
+		heads := e.Heads
+		r.Turn, r.Heads = e.Turn, &heads
+	case directionSpun:
+		r.Turn, r.Direction = e.Turn, e.Direction
+	case pieceMoved:
+		r.Turn, r.Piece, r.From, r.To = e.Turn, e.Piece, e.From, e.To
+	case piecePassed:
+		r.Turn, r.Piece = e.Turn, e.Piece
+	case pieceCaptured:
+		r.Turn, r.Piece, r.By, r.Square = e.Turn, e.Piece, e.By, e.Square
+	case boardShown:
+		r.Turn, r.Diagram = e.Turn, e.Diagram
+	case gameEnded:
+		r.Turn, r.Winner, r.Reason = e.Turn, winnerName(e.Winner), e.Reason
+	}
+	return r
+}
+
+// csvRow converts r into a row matching eventRecordHeader, with seed
+// formatted by seedString.
+func (r eventRecord) csvRow(seed string) []string {
+	roll, heads := "", ""
+	if r.Roll != nil {
+		roll = strconv.Itoa(*r.Roll)
+	}
+	if r.Heads != nil {
+		heads = strconv.FormatBool(*r.Heads)
+	}
+	return []string{seed, r.Type, strconv.Itoa(r.Turn), roll, heads, r.Direction, r.Piece, r.By, r.From, r.To, r.Square, r.Winner, r.Reason, r.Diagram}
+}
+
+// seedString formats seed for output, or returns an empty string if seed is
+// 0 because the game was not played from a seed.
+func seedString(seed int64) string {
+	if seed == 0 {
+		return ""
+	}
+	return strconv.FormatInt(seed, 10)
+}
+
+// writeEvents writes the events of one game played from seed to w in format.
+// Text is the familiar log, JSON an object holding the events and outcome,
+// and CSV one row per event.  A seed of 0 is left out.
+func writeEvents(w io.Writer, format string, seed int64, events []event) error {
+	switch format {
+	case "text":
+		if seed != 0 {
+			if _, err := fmt.Fprintf(w, "Seed: %v\n", seed); err != nil {
+				return err
+			}
+		}
+		for _, l := range renderText(events) {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		out := struct {
+			Seed    int64         `json:"seed,omitempty"`
+			Events  []eventRecord `json:"events"`
+			Outcome *eventRecord  `json:"outcome"`
+		}{Seed: seed, Events: make([]eventRecord, 0, len(events))}
+		for _, e := range events {
+			out.Events = append(out.Events, newEventRecord(e))
+		}
+		if ended, ok := lastGameEnded(events); ok {
+			r := newEventRecord(ended)
+			out.Outcome = &r
+		}
+		return writeJSON(w, out)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(eventRecordHeader); err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := cw.Write(newEventRecord(e).csvRow(seedString(seed))); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return checkFormat(format)
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// outcomeStats is the JSON form of one outcome of a simulation.
+type outcomeStats struct {
+	Reason      string  `json:"reason"`
+	Winner      string  `json:"winner"`
+	Count       int     `json:"count"`
+	Probability float64 `json:"probability"`
+	Low         float64 `json:"ciLow"`
+	High        float64 `json:"ciHigh"`
+}
+
+// lengthStats is the JSON form of how many games lasted a number of turns.
+type lengthStats struct {
+	Turns       int     `json:"turns"`
+	Count       int     `json:"count"`
+	Probability float64 `json:"probability"`
+}
+
+// writeSimulation writes the aggregated result of a simulation seeded with
+// seed to w in format.  CSV holds one row per outcome followed by one row per
+// game length, told apart by the kind column.
+func writeSimulation(w io.Writer, format string, seed int64, r *simulationResult) error {
+	outcomeRows := make([]outcomeStats, 0, len(outcomes))
+	for _, o := range outcomes {
+		p, low, high := r.probability(o.Reason)
+		outcomeRows = append(outcomeRows, outcomeStats{
+			Reason:      o.Reason,
+			Winner:      winnerName(o.Winner),
+			Count:       r.outcomes[o.Reason],
+			Probability: p,
+			Low:         low,
+			High:        high,
+		})
+	}
+	lengthRows := make([]lengthStats, 0, len(r.lengths))
+	for _, l := range r.sortedLengths() {
+		lengthRows = append(lengthRows, lengthStats{
+			Turns:       l,
+			Count:       r.lengths[l],
+			Probability: float64(r.lengths[l]) / float64(r.trials),
+		})
+	}
+	switch format {
+	case "text":
+		if _, err := fmt.Fprintf(w, "Seed: %v\n", seed); err != nil {
+			return err
+		}
+		for _, l := range r.report() {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		return writeJSON(w, struct {
+			Seed     int64          `json:"seed"`
+			Trials   int            `json:"trials"`
+			Outcomes []outcomeStats `json:"outcomes"`
+			Lengths  []lengthStats  `json:"lengths"`
+		}{seed, r.trials, outcomeRows, lengthRows})
+	case "csv":
+		cw := csv.NewWriter(w)
+		rows := [][]string{{"kind", "seed", "reason", "winner", "turns", "count", "probability", "ci_low", "ci_high"}}
+		s := strconv.FormatInt(seed, 10)
+		for _, o := range outcomeRows {
+			rows = append(rows, []string{"outcome", s, o.Reason, o.Winner, "", strconv.Itoa(o.Count), formatFloat(o.Probability), formatFloat(o.Low), formatFloat(o.High)})
+		}
+		for _, l := range lengthRows {
+			rows = append(rows, []string{"length", s, "", "", strconv.Itoa(l.Turns), strconv.Itoa(l.Count), formatFloat(l.Probability), "", ""})
+		}
+		return cw.WriteAll(rows)
+	}
+	return checkFormat(format)
+}
+
+// formatFloat writes f for CSV with enough precision to round trip.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// squareGrid draws a text grid with one cell per square of board, the
+// highest rank at the top, rank numbers down the left and file letters along
+// the bottom.  Each cell is right aligned to width characters.
+func squareGrid(board *internal.Board, width int, cell func(sq internal.Position) string) []string {
+	squares := board.Squares()
+	out := make([]string, 0, board.Height()+1)
+	var sb strings.Builder
+	// Squares lists the ranks from the bottom, so walk its rows backwards.
+	for rank := board.Height() - 1; rank >= 0; rank-- {
+		fmt.Fprintf(&sb, "%v", rank+1)
+		for _, sq := range squares[rank*board.Width() : (rank+1)*board.Width()] {
+			fmt.Fprintf(&sb, " %*s", width, cell(sq))
+		}
+		out = append(out, sb.String())
+		sb.Reset()
+	}
+	sb.WriteString(" ")
+	for file := 0; file < board.Width(); file++ {
+		fmt.Fprintf(&sb, " %*c", width, 'a'+file)
+	}
+	return append(out, sb.String())
+}
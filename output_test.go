@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testEvents(t *testing.T) []event {
+	t.Helper()
+	events, err := evaluateProblem(&loadedCoin{Outcome: []bool{false, true}}, &loadedDice{Outcome: []int{3, 2}}, 2)
+	if err != nil {
+		t.Fatalf("evaluateProblem returned err %v", err)
+	}
+	return events
+}
+
+func TestWriteEvents(t *testing.T) {
+	testCases := []struct {
+		format string
+		seed   int64
+		want   string
+	}{
+		{
+			format: "text",
+			want: "Tails, rolled 3\n" +
+				"Black Rook at c1\n" +
+				"Heads, rolled 2\n" +
+				"Black Rook at c3\n" +
+				"Rook takes bishop, Black wins\n",
+		},
+		{
+			format: "text",
+			seed:   9,
+			want: "Seed: 9\n" +
+				"Tails, rolled 3\n" +
+				"Black Rook at c1\n" +
+				"Heads, rolled 2\n" +
+				"Black Rook at c3\n" +
+				"Rook takes bishop, Black wins\n",
+		},
+		{
+			format: "csv",
+			want: "seed,type,turn,roll,heads,direction,piece,by,from,to,square,winner,reason,diagram\n" +
+				",DiceRolled,1,3,,,,,,,,,,\n" +
+				",CoinTossed,1,,false,,,,,,,,,\n" +
+				",PieceMoved,1,,,,Black Rook,,h1,c1,,,,\n" +
+				",DiceRolled,2,2,,,,,,,,,,\n" +
+				",CoinTossed,2,,true,,,,,,,,,\n" +
+				",PieceCaptured,2,,,,White Bishop,Black Rook,,,c3,,,\n" +
+				",PieceMoved,2,,,,Black Rook,,c1,c3,,,,\n" +
+				",GameEnded,2,,,,,,,,,Black,Rook takes bishop,\n",
+		},
+	}
+	for _, tc := range testCases {
+		var b bytes.Buffer
+		if err := writeEvents(&b, tc.format, tc.seed, testEvents(t)); err != nil {
+			t.Fatalf("writeEvents(%v) returned err %v", tc.format, err)
+		}
+		if got := b.String(); got != tc.want {
+			t.Errorf("writeEvents(%v) = %q, wanted %q", tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestWriteEventsJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeEvents(&b, "json", 11, testEvents(t)); err != nil {
+		t.Fatalf("writeEvents returned err %v", err)
+	}
+	var got struct {
+		Seed    int64
+		Events  []eventRecord
+		Outcome eventRecord
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("writeEvents wrote invalid JSON %v: %v", b.String(), err)
+	}
+	if got.Seed != 11 {
+		t.Errorf("writeEvents wrote seed %v, wanted 11", got.Seed)
+	}
+	if len(got.Events) != 8 {
+		t.Errorf("writeEvents wrote %v events, wanted 8", len(got.Events))
+	}
+	if got.Events[1].Heads == nil || *got.Events[1].Heads {
+		t.Errorf("writeEvents wrote coin toss %+v, wanted tails", got.Events[1])
+	}
+	want := eventRecord{Type: "GameEnded", Turn: 2, Winner: "Black", Reason: rookTakesBishop}
+	if got.Outcome != want {
+		t.Errorf("writeEvents wrote outcome %+v, wanted %+v", got.Outcome, want)
+	}
+
+	// A scenario's dice such as 1d3-1 can roll 0, which must still be written.
+	b.Reset()
+	if err := writeEvents(&b, "json", 0, []event{diceRolled{Turn: 1, Roll: 0}}); err != nil {
+		t.Fatalf("writeEvents returned err %v", err)
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("writeEvents wrote invalid JSON %v: %v", b.String(), err)
+	}
+	if len(got.Events) != 1 || got.Events[0].Roll == nil || *got.Events[0].Roll != 0 {
+		t.Errorf("writeEvents wrote %v, wanted a roll of 0", b.String())
+	}
+	b.Reset()
+	if err := writeEvents(&b, "csv", 0, []event{diceRolled{Turn: 1, Roll: 0}}); err != nil {
+		t.Fatalf("writeEvents returned err %v", err)
+	}
+	if !strings.Contains(b.String(), ",DiceRolled,1,0,") {
+		t.Errorf("writeEvents wrote %v, wanted a roll of 0", b.String())
+	}
+}
+
+func TestWriteSimulation(t *testing.T) {
+	r := newSimulationResult()
+	r.trials = 4
+	r.outcomes[rookTakesBishop] = 1
+	r.outcomes[bishopTakesRook] = 3
+	r.lengths[1] = 3
+	r.lengths[2] = 1
+
+	var b bytes.Buffer
+	if err := writeSimulation(&b, "csv", 7, r); err != nil {
+		t.Fatalf("writeSimulation returned err %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("writeSimulation wrote %v lines, wanted 6: %v", len(lines), lines)
+	}
+	if want := "outcome,7,Bishop can take rook,White,,3,0.75,"; !strings.HasPrefix(lines[2], want) {
+		t.Errorf("writeSimulation wrote %q, wanted prefix %q", lines[2], want)
+	}
+	if want := "length,7,,,2,1,0.25,,"; lines[5] != want {
+		t.Errorf("writeSimulation wrote %q, wanted %q", lines[5], want)
+	}
+
+	b.Reset()
+	if err := writeSimulation(&b, "json", 7, r); err != nil {
+		t.Fatalf("writeSimulation returned err %v", err)
+	}
+	var got struct {
+		Seed     int64
+		Trials   int
+		Outcomes []outcomeStats
+		Lengths  []lengthStats
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("writeSimulation wrote invalid JSON %v: %v", b.String(), err)
+	}
+	if got.Seed != 7 || got.Trials != 4 || len(got.Outcomes) != 3 || len(got.Lengths) != 2 {
+		t.Errorf("writeSimulation wrote %+v", got)
+	}
+
+	if err := writeSimulation(&b, "xml", 7, r); err == nil {
+		t.Errorf("writeSimulation(xml) returned no error")
+	}
+}
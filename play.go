@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// playHelp describes the commands the play REPL understands.
+const playHelp = `Commands:
+  place <letter> <square>  place a piece by FEN letter, upper case for White: place R h1
+  remove <square>          take a piece off the board
+  move <from> <to>         move a piece: move h1 h3, or just h1 h3
+  move <san>               move in algebraic notation: move Rh3, or just Rh3
+  moves [square]           list legal moves for the side to move, or one piece
+  show                     draw the board
+  undo                     take back the last change
+  redo                     make the last move taken back again
+  fen [fen]                print the board as FEN, or set it from FEN
+  save <file>              write the board as FEN to file
+  load <file>              read the board as FEN from file
+  help                     show this help
+  quit                     leave`
+
+// repl is the state of the play subcommand's read-eval-print loop.
+type repl struct {
+	board *internal.Board
+
+	// style is how show draws the board.
+	style internal.RenderStyle
+
+	// history holds each change, most recent last, for undo.
+	history []replChange
+
+	out io.Writer
+}
+
+// replChange records one change made in the REPL so it can be undone.
+type replChange struct {
+	// fen is the board before the change.
+	fen string
+
+	// move is true if the change was a move, which the board can undo
+	// itself while keeping its pieces.
+	move bool
+}
+
+// checkpoint records the board so the next change can be undone.
+func (r *repl) checkpoint() {
+	r.history = append(r.history, replChange{fen: r.board.ToFEN()})
+}
+
+// undo takes back the most recent change.  Moves are taken back by the
+// board, which can redo them.  Anything else, or a move the board no longer
+// remembers because the setup changed since, is restored from FEN.
+func (r *repl) undo() error {
+	if len(r.history) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+	last := r.history[len(r.history)-1]
+	if last.move {
+		if _, err := r.board.Undo(); err == nil {
+			r.forget()
+			return nil
+		}
+	}
+	if err := r.board.FromFEN(last.fen); err != nil {
+		return err
+	}
+	r.forget()
+	return nil
+}
+
+// redo makes the most recently undone move again.
+func (r *repl) redo() error {
+	fen := r.board.ToFEN()
+	m, err := r.board.Redo()
+	if err != nil {
+		return err
+	}
+	r.history = append(r.history, replChange{fen: fen, move: true})
+	fmt.Fprintf(r.out, "%v %v-%v\n", pieceName(m.Piece), m.From, m.To)
+	return nil
+}
+
+// forget drops the checkpoint taken before a change that failed.
+func (r *repl) forget() {
+	r.history = r.history[:len(r.history)-1]
+}
+
+// square parses s as a square on the board.
+func (r *repl) square(s string) (internal.Position, error) {
+	p, err := internal.NewPosition(s)
+	if err != nil {
+		return internal.Position{}, err
+	}
+	if !r.board.Contains(*p) {
+		return internal.Position{}, fmt.Errorf("%v is off the %vx%v board", s, r.board.Width(), r.board.Height())
+	}
+	return *p, nil
+}
+
+// move makes the move described by args, either a source and destination
+// square or a single move in algebraic notation.
+func (r *repl) move(args []string) error {
+	var piece internal.ChessPiece
+	var dest internal.Position
+	switch len(args) {
+	case 1:
+		m, err := r.board.ParseSAN(args[0])
+		if err != nil {
+			return err
+		}
+		piece, dest = m.Piece, m.To
+	case 2:
+		from, err := r.square(args[0])
+		if err != nil {
+			return err
+		}
+		if dest, err = r.square(args[1]); err != nil {
+			return err
+		}
+		if piece = r.board.GetPieceAtPosition(from); piece == nil {
+			return fmt.Errorf("there is no piece on %v", from)
+		}
+	default:
+		return fmt.Errorf("move takes two squares or one algebraic move")
+	}
+	if piece.GetColor() != r.board.SideToMove() {
+		return &internal.OutOfTurnError{Piece: piece, SideToMove: r.board.SideToMove()}
+	}
+	r.checkpoint()
+	r.history[len(r.history)-1].move = true
+	from := *piece.GetPosition()
+	if err := r.board.MovePiece(piece, dest); err != nil {
+		r.forget()
+		return err
+	}
+	// The move itself knows its capture, which may not be on dest for en
+	// passant, and which is nothing for a piece staying put.
+	if m, _ := r.board.LastMove(); m.Captured != nil {
+		fmt.Fprintf(r.out, "%v takes %v on %v\n", pieceName(piece), pieceName(m.Captured), dest)
+	} else {
+		fmt.Fprintf(r.out, "%v %v-%v\n", pieceName(piece), from, dest)
+	}
+	if r.board.InCheck(r.board.SideToMove()) {
+		fmt.Fprintf(r.out, "%v is in check\n", r.board.SideToMove())
+	}
+	return nil
+}
+
+// moves lists the legal moves of the side to move, or only those of the
+// piece on the square in args.
+func (r *repl) moves(args []string) error {
+	var only internal.ChessPiece
+	if len(args) == 1 {
+		sq, err := r.square(args[0])
+		if err != nil {
+			return err
+		}
+		if only = r.board.GetPieceAtPosition(sq); only == nil {
+			return fmt.Errorf("there is no piece on %v", sq)
+		}
+	}
+	names := make([]string, 0)
+	for _, m := range r.board.AllLegalMoves(r.board.SideToMove()) {
+		if only == nil || m.Piece == only {
+			names = append(names, m.String())
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(r.out, "%v has no legal moves\n", r.board.SideToMove())
+		return nil
+	}
+	fmt.Fprintln(r.out, strings.Join(names, " "))
+	return nil
+}
+
+// exec runs one line of input, returning true if the REPL should stop.
+func (r *repl) exec(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+	cmd, args := fields[0], fields[1:]
+	switch cmd {
+	case "quit", "exit":
+		return true, nil
+	case "help":
+		fmt.Fprintln(r.out, playHelp)
+	case "show":
+		fmt.Fprint(r.out, r.board.Render(internal.RenderOptions{Style: r.style}))
+		fmt.Fprintf(r.out, "%v to move\n", r.board.SideToMove())
+	case "place":
+		if len(args) != 2 || len([]rune(args[0])) != 1 {
+			return false, fmt.Errorf("place takes a piece letter and a square")
+		}
+		piece, err := internal.NewPieceFromLetter([]rune(args[0])[0])
+		if err != nil {
+			return false, err
+		}
+		r.checkpoint()
+		if err := r.board.PlacePiece(piece, args[1]); err != nil {
+			r.forget()
+			return false, err
+		}
+	case "remove":
+		if len(args) != 1 {
+			return false, fmt.Errorf("remove takes a square")
+		}
+		sq, err := r.square(args[0])
+		if err != nil {
+			return false, err
+		}
+		r.checkpoint()
+		if _, err := r.board.RemovePiece(sq); err != nil {
+			r.forget()
+			return false, err
+		}
+	case "move":
+		return false, r.move(args)
+	case "moves":
+		return false, r.moves(args)
+	case "undo":
+		return false, r.undo()
+	case "redo":
+		return false, r.redo()
+	case "fen":
+		if len(args) == 0 {
+			fmt.Fprintln(r.out, r.board.ToFEN())
+			return false, nil
+		}
+		return false, r.loadFEN(strings.Join(args, " "))
+	case "save":
+		if len(args) != 1 {
+			return false, fmt.Errorf("save takes a file name")
+		}
+		return false, os.WriteFile(args[0], []byte(r.board.ToFEN()+"\n"), 0644)
+	case "load":
+		if len(args) != 1 {
+			return false, fmt.Errorf("load takes a file name")
+		}
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return false, err
+		}
+		return false, r.loadFEN(strings.TrimSpace(string(data)))
+	default:
+		// Anything else is taken as a move, such as "h1 h3" or "Rh3".
+		return false, r.move(fields)
+	}
+	return false, nil
+}
+
+// loadFEN replaces the board with fen, which can be undone.
+func (r *repl) loadFEN(fen string) error {
+	r.checkpoint()
+	if err := r.board.FromFEN(fen); err != nil {
+		r.forget()
+		return err
+	}
+	return nil
+}
+
+// runPlay implements the play subcommand, reading commands from standard
+// input until it ends or the quit command.
+func runPlay(args []string) error {
+	fs := flag.NewFlagSet("play", flag.ContinueOnError)
+	topology := fs.String("topology", "torus", "which edges wrap: standard, horizontal cylinder, vertical cylinder or torus")
+	width := fs.Int("width", 8, "number of files")
+	height := fs.Int("height", 8, "number of ranks")
+	fen := fs.String("fen", "", "starting position in FEN, empty by default")
+	style := fs.String("style", "ascii", "how to draw the board: ascii or unicode")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	t, err := internal.ParseTopology(*topology)
+	if err != nil {
+		return err
+	}
+	board, err := internal.NewSizedBoard(*width, *height, t)
+	if err != nil {
+		return err
+	}
+	if *fen != "" {
+		if err := board.FromFEN(*fen); err != nil {
+			return err
+		}
+	}
+	s, err := internal.ParseRenderStyle(*style)
+	if err != nil {
+		return err
+	}
+	r := &repl{board: board, style: s, out: os.Stdout}
+	fmt.Fprintln(r.out, "Type help for a list of commands.")
+	return r.run(os.Stdin)
+}
+
+// run executes each line read from in, printing errors and carrying on.
+func (r *repl) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(r.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+		quit, err := r.exec(scanner.Text())
+		if err != nil {
+			fmt.Fprintln(r.out, "Error:", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
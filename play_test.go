@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+func newTestREPL(t *testing.T) (*repl, *bytes.Buffer) {
+	t.Helper()
+	var out bytes.Buffer
+	return &repl{board: internal.NewBoard(), out: &out}, &out
+}
+
+func TestREPLSession(t *testing.T) {
+	testCases := []struct {
+		input   string
+		wantFEN string
+		wantOut []string
+	}{
+		{
+			input:   "place R h1\nplace b c3\nRh3\n",
+			wantFEN: "8/8/8/8/8/2b4R/8/8 b - - 1 1",
+			wantOut: []string{"White Rook h1-h3"},
+		},
+		{
+			// The Bishop is not White's to move, and h1 h8 wraps around.
+			input:   "place R h1\nplace b c3\nc3 h8\nmove h1 h8\n",
+			wantFEN: "7R/8/8/8/8/2b5/8/8 b - - 1 1",
+			wantOut: []string{"Error: Move: Black Bishop at c3 cannot move, it is White's turn", "White Rook h1-h8"},
+		},
+		{
+			input:   "place R h1\nplace b c3\nh1 h3\nc3 h8\nh3 h8\n",
+			wantFEN: "7R/8/8/8/8/8/8/8 b - - 0 2",
+			wantOut: []string{"White Rook takes Black Bishop on h8"},
+		},
+		{
+			// Staying put takes nothing.
+			input:   "place R h1\nh1 h1\n",
+			wantFEN: "8/8/8/8/8/8/8/7R b - - 1 1",
+			wantOut: []string{"White Rook h1-h1"},
+		},
+		{
+			input:   "fen 4k3/8/8/3pP3/8/8/K7/8 w - d6 0 21\ne5 d6\n",
+			wantFEN: "4k3/8/3P4/8/8/8/K7/8 b - - 0 21",
+			wantOut: []string{"White Pawn takes Black Pawn on d6"},
+		},
+		{
+			input:   "place R h1\nplace b c3\nh1 h3\nc3 d4\nundo\nundo\nredo\n",
+			wantFEN: "8/8/8/8/8/2b4R/8/8 b - - 1 1",
+			wantOut: []string{"c3-d4\n> > > White Rook h1-h3"},
+		},
+		{
+			// Undoing a removal restores the board from FEN, after which the
+			// board cannot redo the move.
+			input:   "place R h1\nh1 h3\nremove h3\nundo\nundo\nredo\n",
+			wantFEN: "8/8/8/8/8/8/8/7R w - - 0 1",
+			wantOut: []string{"Error: Redo: no move to make again"},
+		},
+		{
+			input:   "place R h1\nplace b c3\nh1 h3\nundo\nundo\nremove h1\nundo\n",
+			wantFEN: "8/8/8/8/8/8/8/7R w - - 0 1",
+		},
+		{
+			input:   "fen 8/8/8/8/8/8/8/K6r w - - 0 1\nmoves\nmoves a1\nundo\nundo\n",
+			wantFEN: "8/8/8/8/8/8/8/8 w - - 0 1",
+			wantOut: []string{"a1-a2 a1-b2", "Error: nothing to undo"},
+		},
+		{
+			input:   "place Z a1\nplace R z9\nRh3\nmoves\n",
+			wantFEN: "8/8/8/8/8/8/8/8 w - - 0 1",
+			wantOut: []string{"White has no legal moves"},
+		},
+	}
+	for _, tc := range testCases {
+		r, out := newTestREPL(t)
+		if err := r.run(strings.NewReader(tc.input)); err != nil {
+			t.Fatalf("run(%q) returned err %v", tc.input, err)
+		}
+		if got := r.board.ToFEN(); got != tc.wantFEN {
+			t.Errorf("run(%q) left FEN %v, wanted %v", tc.input, got, tc.wantFEN)
+		}
+		for _, want := range tc.wantOut {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("run(%q) printed %q, wanted it to contain %q", tc.input, out.String(), want)
+			}
+		}
+	}
+}
+
+func TestREPLQuitStops(t *testing.T) {
+	r, _ := newTestREPL(t)
+	if err := r.run(strings.NewReader("quit\nplace R a1\n")); err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	if got := r.board.ToFEN(); got != "8/8/8/8/8/8/8/8 w - - 0 1" {
+		t.Errorf("run kept going after quit, FEN %v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// problem holds the parameters of the Rook and Bishop problem.  The zero
+// value is not useful; start from defaultProblem.
+type problem struct {
+	// moves is the number of turns the Rook must survive to escape.
+	moves int
+
+	// rook and bishop are the pieces' starting squares.
+	rook, bishop string
+
+	// heads and tails name the direction the Rook moves on each side of the
+	// coin, using the names scenarios accept such as "up" or "left".
+	heads, tails string
+
+	// board, if set, is the internal.RenderStyle name used to draw the board
+	// after each of the Rook's moves.
+	board string
+}
+
+// defaultProblem returns the problem as originally stated.
+func defaultProblem() problem {
+	return problem{
+		moves:  15,
+		rook:   "h1",
+		bishop: "c3",
+		heads:  "up",
+		tails:  "right",
+	}
+}
+
+// addFlags registers a flag for each parameter on fs, defaulting to the
+// values already in p.
+func (p *problem) addFlags(fs *flag.FlagSet) {
+	fs.IntVar(&p.moves, "moves", p.moves, "number of turns before the Rook escapes")
+	fs.StringVar(&p.rook, "rook", p.rook, "the Rook's starting square")
+	fs.StringVar(&p.bishop, "bishop", p.bishop, "the Bishop's square")
+	fs.StringVar(&p.heads, "heads", p.heads, "direction the Rook moves when the coin lands heads")
+	fs.StringVar(&p.tails, "tails", p.tails, "direction the Rook moves when the coin lands tails")
+}
+
+// addBoardFlag registers the flag asking for a diagram after each move on
+// fs.  Only commands printing single games offer it.
+func (p *problem) addBoardFlag(fs *flag.FlagSet) {
+	fs.StringVar(&p.board, "board", p.board, "draw the board after each move in this style: ascii or unicode")
+}
+
+// validate returns an error describing the first bad parameter in p.
+func (p problem) validate() error {
+	if p.moves < 1 {
+		return fmt.Errorf("moves should be at least 1, got %v", p.moves)
+	}
+	board := internal.NewBoard()
+	for _, sq := range []string{p.rook, p.bishop} {
+		pos, err := internal.NewPosition(sq)
+		if err != nil {
+			return err
+		}
+		if !board.Contains(*pos) {
+			return fmt.Errorf("%v is off the %vx%v board", sq, board.Width(), board.Height())
+		}
+	}
+	if p.rook == p.bishop {
+		return fmt.Errorf("rook and bishop cannot both start on %v", p.rook)
+	}
+	for _, d := range []string{p.heads, p.tails} {
+		step, ok := directions[d]
+		if !ok {
+			return fmt.Errorf("unknown direction %q", d)
+		}
+		if step[0] != 0 && step[1] != 0 {
+			return fmt.Errorf("a Rook cannot move %v", d)
+		}
+	}
+	if p.board != "" {
+		if _, err := internal.ParseRenderStyle(p.board); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// play runs the problem with the given coin and dice, emitting events to the
+// returned slice.  On error the game stops, but events emitted so far are in
+// the output slice.  renderText turns the events into log statements.
+func (p problem) play(c coin, d twoDice) ([]event, error) {
+	out := make([]event, 0)
+	board := internal.NewBoard()
+	rook := internal.NewRook(internal.BLACK)
+	if err := board.PlacePiece(rook, p.rook); err != nil {
+		return out, err
+	}
+	bishop := internal.NewBishop(internal.WHITE)
+	if err := board.PlacePiece(bishop, p.bishop); err != nil {
+		return out, err
+	}
+	// The Rook moves every turn and the Bishop only ever watches, so Black
+	// goes first and White passes unless it can capture.
+	board.SetSideToMove(internal.BLACK)
+	game := internal.NewGame(board)
+	headsStep, tailsStep := directions[p.heads], directions[p.tails]
+	for i := 0; i < p.moves; i++ {
+		turn := i + 1
+		roll := d.Roll()
+		heads := c.Toss()
+		out = append(out, diceRolled{Turn: turn, Roll: roll}, coinTossed{Turn: turn, Heads: heads})
+		step := tailsStep
+		if heads {
+			step = headsStep
+		}
+		moved, err := moveRook(game, rook, turn, step[0]*roll, step[1]*roll)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, moved...)
+		if p.board != "" {
+			style, err := internal.ParseRenderStyle(p.board)
+			if err != nil {
+				return out, err
+			}
+			diagram := board.Render(internal.RenderOptions{Style: style, AttackedBy: internal.WHITE})
+			out = append(out, boardShown{Turn: turn, Diagram: diagram})
+		}
+		if bishop.GetPosition() == nil {
+			return append(out, gameEnded{Turn: turn, Winner: internal.BLACK, Reason: rookTakesBishop}), nil
+		}
+		// Rook should never be nil, panic is fine if it is.
+		if board.IsAttacked(*rook.GetPosition(), internal.WHITE) {
+			return append(out, gameEnded{Turn: turn, Winner: internal.WHITE, Reason: bishopTakesRook}), nil
+		}
+		game.Pass()
+	}
+	return append(out, gameEnded{Turn: p.moves, Winner: internal.BLACK, Reason: rookEscapes}), nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// movesProblem returns the original problem played for n turns.
+func movesProblem(n int) problem {
+	p := defaultProblem()
+	p.moves = n
+	return p
+}
+
+func TestProblemValidate(t *testing.T) {
+	testCases := []struct {
+		change  func(p *problem)
+		wantErr bool
+	}{
+		{func(p *problem) {}, false},
+		{func(p *problem) { p.moves = 0 }, true},
+		{func(p *problem) { p.rook = "i1" }, true},
+		{func(p *problem) { p.bishop = "c0" }, true},
+		{func(p *problem) { p.bishop = "h1" }, true},
+		{func(p *problem) { p.heads = "sideways" }, true},
+		{func(p *problem) { p.tails = "down-left" }, true},
+		{func(p *problem) { p.heads, p.tails = "down", "left" }, false},
+	}
+	for i, tc := range testCases {
+		p := defaultProblem()
+		tc.change(&p)
+		if err := p.validate(); (err != nil) != tc.wantErr {
+			t.Errorf("case %v: validate(%+v) returned err %v, wanted error %v", i, p, err, tc.wantErr)
+		}
+	}
+}
+
+func TestProblemPlay(t *testing.T) {
+	// From a1 with the Bishop on d3, tails moves left 3 around the edge to f1,
+	// which the Bishop attacks.
+	p := problem{moves: 3, rook: "a1", bishop: "d3", heads: "down", tails: "left"}
+	got, err := p.play(&loadedCoin{Outcome: []bool{false}}, &loadedDice{Outcome: []int{3}})
+	if err != nil {
+		t.Fatalf("play returned err %v", err)
+	}
+	want := []event{
+		diceRolled{Turn: 1, Roll: 3},
+		coinTossed{Turn: 1, Heads: false},
+		pieceMoved{Turn: 1, Piece: "Black Rook", From: "a1", To: "f1"},
+		gameEnded{Turn: 1, Winner: internal.WHITE, Reason: bishopTakesRook},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("play = %v, wanted %v", got, want)
+	}
+}
+
+func TestSolveExactMatchesDefaultSquares(t *testing.T) {
+	// Swapping files for ranks maps h1 to a8, leaves c3 alone and turns up
+	// into right, so the mirrored problem has the same odds.
+	want, err := solveExact(movesProblem(4))
+	if err != nil {
+		t.Fatalf("solveExact returned err %v", err)
+	}
+	p := movesProblem(4)
+	p.rook, p.bishop, p.heads, p.tails = "a8", "c3", "right", "up"
+	got, err := solveExact(p)
+	if err != nil {
+		t.Fatalf("solveExact returned err %v", err)
+	}
+	for _, o := range outcomes {
+		if got.total[o.Reason].Cmp(want.total[o.Reason]) != 0 {
+			t.Errorf("mirrored solveExact %v = %v, wanted %v", o, got.total[o.Reason], want.total[o.Reason])
+		}
+	}
+}
+
+func TestProblemPlayShowsBoard(t *testing.T) {
+	p := movesProblem(2)
+	p.board = "ascii"
+	got, err := p.play(&loadedCoin{Outcome: []bool{true, true}}, &loadedDice{Outcome: []int{2, 2}})
+	if err != nil {
+		t.Fatalf("play returned err %v", err)
+	}
+	var diagrams []boardShown
+	for _, e := range got {
+		if b, ok := e.(boardShown); ok {
+			diagrams = append(diagrams, b)
+		}
+	}
+	if len(diagrams) != 2 {
+		t.Fatalf("play showed %v boards, wanted one per move: %v", len(diagrams), got)
+	}
+	// Heads 2 twice takes the Rook from h1 to h5, with c3's diagonals marked.
+	if want := "5 [.] .  .  . [.] .  .  r\n"; !strings.Contains(diagrams[1].Diagram, want) {
+		t.Errorf("second diagram %q does not contain %q", diagrams[1].Diagram, want)
+	}
+}
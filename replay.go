@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadedCoin is a coin returning a recorded sequence of tosses.
+type loadedCoin struct {
+	// Outcome is the list of flips that will be returned.
+	Outcome []bool
+
+	// Exhausted is set if the coin was tossed more times than Outcome
+	// holds.  Those extra tosses return tails.
+	Exhausted bool
+}
+
+func (l *loadedCoin) Toss() bool {
+	if len(l.Outcome) == 0 {
+		l.Exhausted = true
+		return false
+	}
+	out, remainder := l.Outcome[0], l.Outcome[1:]
+	l.Outcome = remainder
+	return out
+}
+
+// loadedDice is a pair of dice returning a recorded sequence of rolls.
+type loadedDice struct {
+	// Outcome is the list of rolls that will be returned.
+	Outcome []int
+
+	// Exhausted is set if the dice were rolled more times than Outcome
+	// holds.  Those extra rolls return 2.
+	Exhausted bool
+}
+
+func (l *loadedDice) Roll() int {
+	if len(l.Outcome) == 0 {
+		l.Exhausted = true
+		return 2
+	}
+	out, remainder := l.Outcome[0], l.Outcome[1:]
+	l.Outcome = remainder
+	return out
+}
+
+// parseTosses parses a comma separated list of coin tosses such as "H,T" or
+// "heads,tails".
+func parseTosses(s string) ([]bool, error) {
+	out := make([]bool, 0)
+	for _, t := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(t)) {
+		case "h", "heads":
+			out = append(out, true)
+		case "t", "tails":
+			out = append(out, false)
+		default:
+			return nil, fmt.Errorf("toss should be heads or tails, got %q", t)
+		}
+	}
+	return out, nil
+}
+
+// parseRolls parses a comma separated list of rolls of two six sided dice.
+func parseRolls(s string) ([]int, error) {
+	out := make([]int, 0)
+	for _, r := range strings.Split(s, ",") {
+		roll, err := strconv.Atoi(strings.TrimSpace(r))
+		if err != nil || roll < 2 || roll > 12 {
+			return nil, fmt.Errorf("roll should be a number from 2 to 12, got %q", r)
+		}
+		out = append(out, roll)
+	}
+	return out, nil
+}
+
+// replay plays p with the recorded tosses and rolls, returning an error if
+// the game needed more of either than were recorded.
+func replay(p problem, tosses []bool, rolls []int) ([]event, error) {
+	c := &loadedCoin{Outcome: tosses}
+	d := &loadedDice{Outcome: rolls}
+	events, err := p.play(c, d)
+	if err != nil {
+		return events, err
+	}
+	if c.Exhausted || d.Exhausted {
+		return events, fmt.Errorf("the game needed more than %v tosses and %v rolls", len(tosses), len(rolls))
+	}
+	return events, nil
+}
+
+// runReplay implements the replay subcommand.
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	tossList := fs.String("tosses", "", "comma separated coin tosses, such as H,T,T")
+	rollList := fs.String("rolls", "", "comma separated rolls of two dice, such as 7,3,11")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	svgPath := fs.String("svg", "", "file to draw the final position and the Rook's moves in as SVG")
+	gifPath := fs.String("gif", "", "file to write an animated GIF of the game to")
+	p := defaultProblem()
+	p.addFlags(fs)
+	p.addBoardFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	tosses, err := parseTosses(*tossList)
+	if err != nil {
+		return err
+	}
+	rolls, err := parseRolls(*rollList)
+	if err != nil {
+		return err
+	}
+	events, err := replay(p, tosses, rolls)
+	if err != nil {
+		return err
+	}
+	if err := writeEvents(os.Stdout, *format, 0, events); err != nil {
+		return err
+	}
+	if *svgPath != "" {
+		if err := writeGameSVG(*svgPath, p, events); err != nil {
+			return err
+		}
+	}
+	if *gifPath != "" {
+		return writeGameGIF(*gifPath, p, events)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParseTosses(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    []bool
+		wantErr bool
+	}{
+		{"H,T", []bool{true, false}, false},
+		{"heads, Tails,h", []bool{true, false, true}, false},
+		{"H,X", nil, true},
+		{"", nil, true},
+	}
+	for _, tc := range testCases {
+		got, err := parseTosses(tc.in)
+		if (err != nil) != tc.wantErr || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseTosses(%q) = %v, %v, wanted %v, error %v", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestParseRolls(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{"7,3, 12", []int{7, 3, 12}, false},
+		{"1", nil, true},
+		{"13", nil, true},
+		{"7,x", nil, true},
+	}
+	for _, tc := range testCases {
+		got, err := parseRolls(tc.in)
+		if (err != nil) != tc.wantErr || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseRolls(%q) = %v, %v, wanted %v, error %v", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestReplayMatchesSeededGame(t *testing.T) {
+	rng := rand.New(rand.NewSource(12345))
+	want, err := defaultProblem().play(&seededCoin{rng: rng}, &seededDice{rng: rng})
+	if err != nil {
+		t.Fatalf("play returned err %v", err)
+	}
+	tosses := make([]bool, 0)
+	rolls := make([]int, 0)
+	for _, e := range want {
+		switch e := e.(type) {
+		case coinTossed:
+			tosses = append(tosses, e.Heads)
+		case diceRolled:
+			rolls = append(rolls, e.Roll)
+		}
+	}
+	got, err := replay(defaultProblem(), tosses, rolls)
+	if err != nil {
+		t.Fatalf("replay returned err %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replay = %v, wanted %v", got, want)
+	}
+}
+
+func TestReplayRunsOut(t *testing.T) {
+	// Heads 2 from h1 lands on h3, which is safe, and then nothing is left.
+	if _, err := replay(defaultProblem(), []bool{true}, []int{2}); err == nil {
+		t.Errorf("replay with too few tosses returned no error")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/Techbert08/ChessProblem/internal/randomizer"
+)
+
+// seededCoin is a fair coin drawing from its own random source rather than
+// the global one shared by realCoin.
+type seededCoin struct {
+	rng *rand.Rand
+}
+
+func (s *seededCoin) Toss() bool {
+	return randomizer.FairCoin().Toss(s.rng)
+}
+
+// seededDice is a pair of six sided dice drawing from its own random source.
+type seededDice struct {
+	rng *rand.Rand
+}
+
+func (s *seededDice) Roll() int {
+	return randomizer.TwoD6().Sample(s.rng)
+}
+
+// chunkSize is the number of trials played from each chunk's random source.
+// Trials are always split into the same chunks, so results do not depend on
+// how many workers share them out.
+const chunkSize = 4096
+
+// chunkSeed derives the seed for chunk i of a run seeded with seed, mixing
+// the two with SplitMix64 so neighbouring chunks are uncorrelated.
+func chunkSeed(seed int64, i int) int64 {
+	z := uint64(seed) + uint64(i+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// merge adds the games recorded in other to r.
+func (r *simulationResult) merge(other *simulationResult) {
+	r.trials += other.trials
+	for o, n := range other.outcomes {
+		r.outcomes[o] += n
+	}
+	for l, n := range other.lengths {
+		r.lengths[l] += n
+	}
+}
+
+// simulateParallel plays trials games of p across workers
+// goroutines.  Each chunk of trials draws from its own source seeded from
+// seed, so a given seed produces the same result for any number of workers.
+func simulateParallel(seed int64, p problem, trials, workers int) (*simulationResult, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	numChunks := (trials + chunkSize - 1) / chunkSize
+	chunks := make(chan int)
+	results := make(chan *simulationResult)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range chunks {
+				rng := rand.New(rand.NewSource(chunkSeed(seed, i)))
+				n := chunkSize
+				if last := trials - i*chunkSize; last < n {
+					n = last
+				}
+				r, err := simulate(&seededCoin{rng: rng}, &seededDice{rng: rng}, p, n)
+				if err != nil {
+					// Keep draining chunks so the producer is not left blocked.
+					errOnce.Do(func() { firstErr = err })
+					continue
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < numChunks; i++ {
+			chunks <- i
+		}
+		close(chunks)
+		wg.Wait()
+		close(results)
+	}()
+
+	total := newSimulationResult()
+	for r := range results {
+		total.merge(r)
+	}
+	// results is only closed after every worker is done, so firstErr is settled.
+	return total, firstErr
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimulateParallelDeterministic(t *testing.T) {
+	// Deliberately not a multiple of chunkSize.
+	trials := 3*chunkSize + 17
+	want, err := simulateParallel(42, defaultProblem(), trials, 1)
+	if err != nil {
+		t.Fatalf("simulateParallel with 1 worker returned err %v", err)
+	}
+	if want.trials != trials {
+		t.Errorf("simulateParallel played %v trials, wanted %v", want.trials, trials)
+	}
+	for _, workers := range []int{2, 3, 8} {
+		got, err := simulateParallel(42, defaultProblem(), trials, workers)
+		if err != nil {
+			t.Fatalf("simulateParallel with %v workers returned err %v", workers, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("simulateParallel with %v workers = %+v, wanted %+v", workers, got, want)
+		}
+	}
+	other, err := simulateParallel(43, defaultProblem(), trials, 4)
+	if err != nil {
+		t.Fatalf("simulateParallel returned err %v", err)
+	}
+	if reflect.DeepEqual(other, want) {
+		t.Errorf("simulateParallel with different seeds returned identical results %+v", other)
+	}
+}
+
+func TestChunkSeedDistinct(t *testing.T) {
+	seen := make(map[int64]bool)
+	for _, seed := range []int64{0, 1, 2} {
+		for i := 0; i < 100; i++ {
+			s := chunkSeed(seed, i)
+			if seen[s] {
+				t.Errorf("chunkSeed(%v, %v) = %v repeats an earlier seed", seed, i, s)
+			}
+			seen[s] = true
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"unicode"
+
+	"github.com/Techbert08/ChessProblem/internal"
+	"github.com/Techbert08/ChessProblem/internal/randomizer"
+)
+
+// scenario describes a variant of the problem: which pieces start where, how
+// the randomly moving pieces move, and how the game is won.  It is loaded
+// from JSON, see scenarios/rook_vs_bishop.json for the original problem.
+type scenario struct {
+	// Topology is a name accepted by internal.ParseTopology.  Defaults to
+	// "torus".
+	Topology string `json:"topology"`
+
+	// Width and Height are the board's dimensions.  Both default to 8.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// TurnLimit is the number of turns played before the turnLimit win
+	// condition applies.
+	TurnLimit int `json:"turnLimit"`
+
+	// Pieces start on the board in this order, and move in this order
+	// each turn.
+	Pieces []scenarioPiece `json:"pieces"`
+
+	// WinConditions are checked in order after every move.
+	WinConditions []winCondition `json:"winConditions"`
+}
+
+// scenarioPiece is a single piece in a scenario.
+type scenarioPiece struct {
+	// ID names this piece for win conditions.
+	ID string `json:"id"`
+
+	// Color is "white" or "black".
+	Color string `json:"color"`
+
+	// Type is a piece name such as "rook" or "knight".
+	Type string `json:"type"`
+
+	// Square is where the piece starts, such as "h1".
+	Square string `json:"square"`
+
+	// Policy is how this piece moves each turn, or nil if it never moves.
+	Policy *movementPolicy `json:"policy"`
+}
+
+// movementPolicy moves a piece a random distance in a random direction.
+type movementPolicy struct {
+	// Directions holds the direction names to choose between.  With two, a
+	// coin toss picks the first on heads and the second on tails.  With more,
+	// a spinner picks one.
+	Directions []string `json:"directions"`
+
+	// Weights optionally biases the coin or spinner, with one weight per
+	// direction.  Directions are equally likely if omitted.
+	Weights []int64 `json:"weights"`
+
+	// Distance is dice notation such as "2d6" or "1d4+1", or a fixed number
+	// of squares.  A piece that cannot legally move that far, say off an edge
+	// that does not wrap, moves as far as it legally can in its direction,
+	// and passes if it cannot even stay put.  As in the original problem, a
+	// wrapping board judges the destination, not the path, so a piece may
+	// land beyond a blocker it could reach the other way round.
+	Distance string `json:"distance"`
+}
+
+// chooser returns the coin or spinner picking among Directions.  The
+// policy must already be validated.
+func (m *movementPolicy) chooser() (randomizer.Distribution, error) {
+	weights := m.Weights
+	if len(weights) == 0 {
+		weights = make([]int64, len(m.Directions))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) == 2 {
+		// Heads picks the first direction and tails the second.
+		if weights[0] < 0 || weights[1] < 0 || weights[0]+weights[1] == 0 {
+			return nil, fmt.Errorf("weights should not be negative or all zero, got %v", weights)
+		}
+		return randomizer.NewCoin(big.NewRat(weights[0], weights[0]+weights[1]))
+	}
+	return randomizer.NewSpinner(weights...)
+}
+
+// drawer draws values from randomizer distributions, letting a scenario run
+// against real randomness or loaded values alike.
+type drawer interface {
+	Draw(d randomizer.Distribution) int
+}
+
+// rngDrawer samples each distribution from rng, or the global math/rand
+// source if rng is nil.
+type rngDrawer struct {
+	rng *rand.Rand
+}
+
+func (r rngDrawer) Draw(d randomizer.Distribution) int {
+	return d.Sample(r.rng)
+}
+
+// winCondition ends the game with a winner when it is met.
+type winCondition struct {
+	// Type is one of:
+	//   "captured": Piece has been taken.
+	//   "attacked": Piece could be taken by any opposing piece.
+	//   "turnLimit": TurnLimit turns were played.
+	Type string `json:"type"`
+
+	// Piece is the ID of the piece this condition watches.
+	Piece string `json:"piece"`
+
+	// Winner is "white" or "black".
+	Winner string `json:"winner"`
+
+	// Reason is logged ahead of the winner when this condition is met.
+	Reason string `json:"reason"`
+}
+
+// directions maps each direction name to its (file, rank) step.
+var directions = map[string][2]int{
+	"up":         {0, 1},
+	"down":       {0, -1},
+	"right":      {1, 0},
+	"left":       {-1, 0},
+	"up-right":   {1, 1},
+	"up-left":    {-1, 1},
+	"down-right": {1, -1},
+	"down-left":  {-1, -1},
+}
+
+// pieceLetters maps each piece type to its FEN letter.
+var pieceLetters = map[string]rune{
+	"rook":   'r',
+	"bishop": 'b',
+	"knight": 'n',
+	"queen":  'q',
+	"king":   'k',
+	"pawn":   'p',
+}
+
+// canMove returns true if a piece of type pieceType and Color c can move
+// along the direction step.  Knights never move along a straight line, and
+// Pawns only advance.
+func canMove(pieceType string, c internal.Color, step [2]int) bool {
+	switch pieceType {
+	case "rook":
+		return step[0] == 0 || step[1] == 0
+	case "bishop":
+		return step[0] != 0 && step[1] != 0
+	case "queen", "king":
+		return true
+	case "pawn":
+		forward := 1
+		if c == internal.BLACK {
+			forward = -1
+		}
+		return step == [2]int{0, forward}
+	}
+	return false
+}
+
+// parseColor converts "white" or "black" into a Color.
+func parseColor(name string) (internal.Color, error) {
+	switch name {
+	case "white":
+		return internal.WHITE, nil
+	case "black":
+		return internal.BLACK, nil
+	}
+	return internal.EMPTY, fmt.Errorf("color should be white or black, got %q", name)
+}
+
+// loadScenario reads and validates a scenario from a JSON file.
+func loadScenario(path string) (*scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseScenario(data)
+}
+
+// parseScenario decodes and validates a scenario from JSON, filling in
+// defaults.
+func parseScenario(data []byte) (*scenario, error) {
+	s := &scenario{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("scenario: %w", err)
+	}
+	if s.Topology == "" {
+		s.Topology = internal.TORUS.String()
+	}
+	if s.Width == 0 {
+		s.Width = 8
+	}
+	if s.Height == 0 {
+		s.Height = 8
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("scenario: %w", err)
+	}
+	return s, nil
+}
+
+// validate checks everything that can be checked before a game is played.
+func (s *scenario) validate() error {
+	topology, err := internal.ParseTopology(s.Topology)
+	if err != nil {
+		return err
+	}
+	board, err := internal.NewSizedBoard(s.Width, s.Height, topology)
+	if err != nil {
+		return err
+	}
+	if s.TurnLimit < 1 {
+		return fmt.Errorf("turnLimit should be at least 1, got %v", s.TurnLimit)
+	}
+	ids := make(map[string]bool)
+	// squares holds the ID of the piece starting on each square.
+	squares := make(map[internal.Position]string)
+	for _, p := range s.Pieces {
+		if p.ID == "" || ids[p.ID] {
+			return fmt.Errorf("piece ids should be unique and not empty, got %q", p.ID)
+		}
+		ids[p.ID] = true
+		color, err := parseColor(p.Color)
+		if err != nil {
+			return fmt.Errorf("piece %v: %w", p.ID, err)
+		}
+		if _, ok := pieceLetters[p.Type]; !ok {
+			return fmt.Errorf("piece %v: unknown type %q", p.ID, p.Type)
+		}
+		pos, err := internal.NewPosition(p.Square)
+		if err != nil {
+			return fmt.Errorf("piece %v: %w", p.ID, err)
+		}
+		if !board.Contains(*pos) {
+			return fmt.Errorf("piece %v: %v is off the %vx%v board", p.ID, p.Square, s.Width, s.Height)
+		}
+		if other, ok := squares[*pos]; ok {
+			return fmt.Errorf("piece %v: %v is already taken by piece %v", p.ID, p.Square, other)
+		}
+		squares[*pos] = p.ID
+		if p.Policy == nil {
+			continue
+		}
+		if len(p.Policy.Directions) < 1 {
+			return fmt.Errorf("piece %v: policy should have at least one direction", p.ID)
+		}
+		for _, d := range p.Policy.Directions {
+			step, ok := directions[d]
+			if !ok {
+				return fmt.Errorf("piece %v: unknown direction %q", p.ID, d)
+			}
+			if !canMove(p.Type, color, step) {
+				return fmt.Errorf("piece %v: a %v cannot move %v", p.ID, p.Type, d)
+			}
+		}
+		if w := len(p.Policy.Weights); w != 0 && w != len(p.Policy.Directions) {
+			return fmt.Errorf("piece %v: policy should have one weight per direction, got %v", p.ID, w)
+		}
+		if _, err := p.Policy.chooser(); err != nil {
+			return fmt.Errorf("piece %v: %w", p.ID, err)
+		}
+		if _, err := randomizer.ParseDice(p.Policy.Distance); err != nil {
+			return fmt.Errorf("piece %v: %w", p.ID, err)
+		}
+	}
+	for _, w := range s.WinConditions {
+		if _, err := parseColor(w.Winner); err != nil {
+			return fmt.Errorf("win condition %v: %w", w.Type, err)
+		}
+		switch w.Type {
+		case "captured", "attacked":
+			if !ids[w.Piece] {
+				return fmt.Errorf("win condition %v: unknown piece %q", w.Type, w.Piece)
+			}
+		case "turnLimit":
+		default:
+			return fmt.Errorf("unknown win condition type %q", w.Type)
+		}
+	}
+	return nil
+}
+
+// setup builds the board described by s, returning it with each piece
+// keyed by ID.
+func (s *scenario) setup() (*internal.Board, map[string]internal.ChessPiece, error) {
+	topology, err := internal.ParseTopology(s.Topology)
+	if err != nil {
+		return nil, nil, err
+	}
+	board, err := internal.NewSizedBoard(s.Width, s.Height, topology)
+	if err != nil {
+		return nil, nil, err
+	}
+	pieces := make(map[string]internal.ChessPiece)
+	for _, p := range s.Pieces {
+		letter := pieceLetters[p.Type]
+		if p.Color == "white" {
+			letter = unicode.ToUpper(letter)
+		}
+		piece, err := internal.NewPieceFromLetter(letter)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := board.PlacePiece(piece, p.Square); err != nil {
+			return nil, nil, err
+		}
+		pieces[p.ID] = piece
+	}
+	return board, pieces, nil
+}
+
+// isAttacked returns true if any piece opposing piece could take it.
+func isAttacked(board *internal.Board, piece internal.ChessPiece) bool {
+	pos := piece.GetPosition()
+	if pos == nil {
+		return false
+	}
+	opponent := internal.WHITE
+	if piece.GetColor() == internal.WHITE {
+		opponent = internal.BLACK
+	}
+	return board.IsAttacked(*pos, opponent)
+}
+
+// checkWin returns the gameEnded event of the first met win condition, or
+// false if the game goes on.  turnLimitReached enables turnLimit conditions.
+func (s *scenario) checkWin(board *internal.Board, pieces map[string]internal.ChessPiece, turn int, turnLimitReached bool) (gameEnded, bool) {
+	for _, w := range s.WinConditions {
+		met := false
+		switch w.Type {
+		case "captured":
+			met = pieces[w.Piece].GetPosition() == nil
+		case "attacked":
+			met = isAttacked(board, pieces[w.Piece])
+		case "turnLimit":
+			met = turnLimitReached
+		}
+		if met {
+			winner, _ := parseColor(w.Winner)
+			return gameEnded{Turn: turn, Winner: winner, Reason: w.Reason}, true
+		}
+	}
+	return gameEnded{}, false
+}
+
+// move makes one random move of piece according to policy, emitting events
+// in the same form as evaluateProblem.  The distance is drawn before the
+// direction, as evaluateProblem rolls before tossing.
+func (s *scenario) move(board *internal.Board, piece internal.ChessPiece, policy *movementPolicy, dr drawer, turn int, out []event) ([]event, error) {
+	dice, err := randomizer.ParseDice(policy.Distance)
+	if err != nil {
+		return out, err
+	}
+	chooser, err := policy.chooser()
+	if err != nil {
+		return out, err
+	}
+	distance := dice.Modifier
+	if dice.Count > 0 {
+		distance = dr.Draw(dice)
+		out = append(out, diceRolled{Turn: turn, Roll: distance})
+	}
+	direction := policy.Directions[0]
+	switch len(policy.Directions) {
+	case 1:
+	case 2:
+		heads := dr.Draw(chooser) == 1
+		if !heads {
+			direction = policy.Directions[1]
+		}
+		out = append(out, coinTossed{Turn: turn, Heads: heads})
+	default:
+		i := dr.Draw(chooser)
+		if i < 0 || i >= len(policy.Directions) {
+			return out, fmt.Errorf("spinner landed on %v, which has no direction", i)
+		}
+		direction = policy.Directions[i]
+		out = append(out, directionSpun{Turn: turn, Direction: direction})
+	}
+	step := directions[direction]
+	from := *piece.GetPosition()
+	// Try the full distance first, then ever shorter ones, so an edge or a
+	// blocked destination stops the piece short.  Even staying put fails if
+	// it leaves the piece's own King in check, and then the piece passes.
+	for n := distance; n >= 0; n-- {
+		dest, ok := board.Step(from, step[0]*n, step[1]*n)
+		if !ok {
+			continue
+		}
+		if err := board.MovePiece(piece, dest); err != nil {
+			continue
+		}
+		m, _ := board.LastMove()
+		return append(out, moveEvents(turn, piece, from, dest, m.Captured)...), nil
+	}
+	return append(out, piecePassed{Turn: turn, Piece: pieceName(piece)}), nil
+}
+
+// run plays the scenario once with random values from dr, emitting events
+// to the returned slice just as evaluateProblem does.
+func (s *scenario) run(dr drawer) ([]event, error) {
+	out := make([]event, 0)
+	board, pieces, err := s.setup()
+	if err != nil {
+		return out, err
+	}
+	for turn := 1; turn <= s.TurnLimit; turn++ {
+		for _, p := range s.Pieces {
+			piece := pieces[p.ID]
+			if p.Policy == nil || piece.GetPosition() == nil {
+				continue
+			}
+			if out, err = s.move(board, piece, p.Policy, dr, turn, out); err != nil {
+				return out, err
+			}
+			if result, ok := s.checkWin(board, pieces, turn, false); ok {
+				return append(out, result), nil
+			}
+		}
+	}
+	if result, ok := s.checkWin(board, pieces, s.TurnLimit, true); ok {
+		return append(out, result), nil
+	}
+	return append(out, gameEnded{Turn: s.TurnLimit, Winner: internal.EMPTY, Reason: "Turn limit reached"}), nil
+}
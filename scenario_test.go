@@ -0,0 +1,277 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/Techbert08/ChessProblem/internal/randomizer"
+)
+
+// coinDiceDrawer answers every coin toss from c, 1 for heads and 0 for
+// tails, and every other draw, spinners included, from d.
+type coinDiceDrawer struct {
+	c coin
+	d twoDice
+}
+
+func (cd coinDiceDrawer) Draw(d randomizer.Distribution) int {
+	if _, ok := d.(*randomizer.Coin); ok {
+		if cd.c.Toss() {
+			return 1
+		}
+		return 0
+	}
+	return cd.d.Roll()
+}
+
+func TestRookVsBishopScenarioMatchesProblem(t *testing.T) {
+	s, err := loadScenario("scenarios/rook_vs_bishop.json")
+	if err != nil {
+		t.Fatalf("loadScenario returned err %v", err)
+	}
+	for _, tc := range testCases {
+		s.TurnLimit = tc.numTurns
+		events, err := s.run(coinDiceDrawer{&loadedCoin{Outcome: tc.coin}, &loadedDice{Outcome: tc.dice}})
+		if err != nil {
+			t.Errorf("run(%v,%v) returned err: %v", tc.coin, tc.dice, err)
+		}
+		want, _ := evaluateProblem(&loadedCoin{Outcome: tc.coin}, &loadedDice{Outcome: tc.dice}, tc.numTurns)
+		if !reflect.DeepEqual(events, want) {
+			t.Errorf("run(%v,%v) = %v, wanted %v", tc.coin, tc.dice, events, want)
+		}
+		if got := renderText(events); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("run(%v,%v) = %v, wanted %v", tc.coin, tc.dice, got, tc.want)
+		}
+	}
+}
+
+func TestScenarioStandardTopology(t *testing.T) {
+	s, err := parseScenario([]byte(`{
+		"topology": "standard",
+		"width": 5,
+		"height": 5,
+		"turnLimit": 3,
+		"pieces": [
+			{"id": "r", "color": "white", "type": "rook", "square": "a1",
+			 "policy": {"directions": ["up", "right"], "distance": "2d6"}},
+			{"id": "p", "color": "white", "type": "pawn", "square": "c5"},
+			{"id": "q", "color": "black", "type": "queen", "square": "e1"}
+		],
+		"winConditions": [
+			{"type": "captured", "piece": "q", "winner": "white", "reason": "Rook takes queen"},
+			{"type": "turnLimit", "winner": "black", "reason": "Queen survives"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseScenario returned err %v", err)
+	}
+
+	// The Rook stops at the top edge, then short of its own Pawn, and then
+	// stays put as it can go no higher.
+	events, err := s.run(coinDiceDrawer{&loadedCoin{Outcome: []bool{true, false, true}}, &loadedDice{Outcome: []int{7, 3, 4}}})
+	if err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	want := []string{
+		"Heads, rolled 7",
+		"White Rook at a5",
+		"Tails, rolled 3",
+		"White Rook at b5",
+		"Heads, rolled 4",
+		"White Rook at b5",
+		"Queen survives, Black wins",
+	}
+	if got := renderText(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("run = %v, wanted %v", got, want)
+	}
+
+	// However far it rolls, the Rook stops to take the Queen in its path.
+	events, err = s.run(coinDiceDrawer{&loadedCoin{Outcome: []bool{false}}, &loadedDice{Outcome: []int{12}}})
+	if err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	if ended, ok := lastGameEnded(events); !ok || ended.Reason != "Rook takes queen" {
+		t.Errorf("run = %v, wanted the Rook to take the Queen", renderText(events))
+	}
+
+	// Real randomness must always produce a finished game.
+	for seed := int64(1); seed <= 50; seed++ {
+		events, err := s.run(rngDrawer{rng: rand.New(rand.NewSource(seed))})
+		if _, ok := lastGameEnded(events); err != nil || !ok {
+			t.Errorf("run with seed %v = %v, %v", seed, renderText(events), err)
+		}
+	}
+}
+
+func TestScenarioTwoMovers(t *testing.T) {
+	s, err := parseScenario([]byte(`{
+		"turnLimit": 2,
+		"pieces": [
+			{"id": "r1", "color": "black", "type": "rook", "square": "a1",
+			 "policy": {"directions": ["right"], "distance": "1"}},
+			{"id": "r2", "color": "white", "type": "rook", "square": "h8",
+			 "policy": {"directions": ["down", "left"], "distance": "2d6"}}
+		],
+		"winConditions": [
+			{"type": "captured", "piece": "r2", "winner": "black", "reason": "Black captures"},
+			{"type": "captured", "piece": "r1", "winner": "white", "reason": "White captures"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseScenario returned err %v", err)
+	}
+
+	events, err := s.run(coinDiceDrawer{&loadedCoin{Outcome: []bool{true, false}}, &loadedDice{Outcome: []int{7, 7}}})
+	got := renderText(events)
+	if err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	want := []string{
+		"Black Rook at b1",
+		"Heads, rolled 7",
+		"White Rook at h1",
+		"Black Rook at c1",
+		"Tails, rolled 7",
+		"White Rook at a1",
+		"Turn limit reached, nobody wins",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("run = %v, wanted %v", got, want)
+	}
+}
+
+func TestScenarioTorusJudgesDestination(t *testing.T) {
+	s, err := parseScenario([]byte(`{
+		"turnLimit": 1,
+		"pieces": [
+			{"id": "r", "color": "white", "type": "rook", "square": "a1",
+			 "policy": {"directions": ["up"], "distance": "3"}},
+			{"id": "b", "color": "black", "type": "bishop", "square": "a3"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseScenario returned err %v", err)
+	}
+
+	// The Bishop blocks a1 to a4 going up, but not going down and around.
+	events, err := s.run(coinDiceDrawer{&loadedCoin{}, &loadedDice{}})
+	if err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	want := []string{
+		"White Rook at a4",
+		"Turn limit reached, nobody wins",
+	}
+	if got := renderText(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("run = %v, wanted %v", got, want)
+	}
+}
+
+func TestScenarioPassesInCheck(t *testing.T) {
+	s, err := parseScenario([]byte(`{
+		"topology": "standard",
+		"turnLimit": 2,
+		"pieces": [
+			{"id": "k", "color": "white", "type": "king", "square": "e1"},
+			{"id": "wr", "color": "white", "type": "rook", "square": "a3",
+			 "policy": {"directions": ["up"], "distance": "1"}},
+			{"id": "br", "color": "black", "type": "rook", "square": "h2",
+			 "policy": {"directions": ["down"], "distance": "1"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseScenario returned err %v", err)
+	}
+
+	// Once the Black Rook checks from h1, the White Rook can neither move up
+	// nor stay put, so it passes.
+	events, err := s.run(coinDiceDrawer{&loadedCoin{}, &loadedDice{}})
+	if err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	want := []string{
+		"White Rook at a4",
+		"Black Rook at h1",
+		"White Rook passes",
+		"Black Rook at h1",
+		"Turn limit reached, nobody wins",
+	}
+	if got := renderText(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("run = %v, wanted %v", got, want)
+	}
+}
+
+var parseScenarioErrorTestCases = []struct {
+	json string
+	want string
+}{
+	{`{`, "scenario: unexpected end of JSON input"},
+	{`{"turnLimit": 0}`, "scenario: turnLimit should be at least 1, got 0"},
+	{`{"turnLimit": 1, "topology": "sphere"}`, `scenario: ParseTopology: unknown topology "sphere"`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "red", "type": "rook"}]}`, `scenario: piece a: color should be white or black, got "red"`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1"}, {"id": "a", "color": "white", "type": "rook", "square": "b1"}]}`, `scenario: piece ids should be unique and not empty, got "a"`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "dragon"}]}`, `scenario: piece a: unknown type "dragon"`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1", "policy": {"directions": ["sideways"], "distance": "1"}}]}`, `scenario: piece a: unknown direction "sideways"`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1", "policy": {"directions": ["up"], "distance": "3x4"}}]}`, `scenario: piece a: ParseDice: expected NdS+M notation, got "3x4"`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1", "policy": {"directions": [], "distance": "1"}}]}`, `scenario: piece a: policy should have at least one direction`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1", "policy": {"directions": ["up", "down"], "weights": [1], "distance": "1"}}]}`, `scenario: piece a: policy should have one weight per direction, got 1`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1", "policy": {"directions": ["up", "down", "left"], "weights": [0, 0, 0], "distance": "1"}}]}`, `scenario: piece a: NewSpinner: NewDiscrete: weights should not all be zero`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "i1"}]}`, `scenario: piece a: i1 is off the 8x8 board`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook"}]}`, `scenario: piece a: NewPosition: invalid string. expected a file letter and rank number, got `},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "rook", "square": "a1"}, {"id": "b", "color": "black", "type": "rook", "square": "a1"}]}`, `scenario: piece b: a1 is already taken by piece a`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "knight", "square": "a1", "policy": {"directions": ["up-right"], "distance": "1"}}]}`, `scenario: piece a: a knight cannot move up-right`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "white", "type": "bishop", "square": "a1", "policy": {"directions": ["up"], "distance": "1"}}]}`, `scenario: piece a: a bishop cannot move up`},
+	{`{"turnLimit": 1, "pieces": [{"id": "a", "color": "black", "type": "pawn", "square": "a7", "policy": {"directions": ["up"], "distance": "1"}}]}`, `scenario: piece a: a pawn cannot move up`},
+	{`{"turnLimit": 1, "winConditions": [{"type": "captured", "piece": "x", "winner": "white"}]}`, `scenario: win condition captured: unknown piece "x"`},
+	{`{"turnLimit": 1, "winConditions": [{"type": "resign", "winner": "white"}]}`, `scenario: unknown win condition type "resign"`},
+}
+
+func TestParseScenarioErrors(t *testing.T) {
+	for _, tc := range parseScenarioErrorTestCases {
+		_, err := parseScenario([]byte(tc.json))
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("parseScenario(%v) returned err %v, wanted %v", tc.json, err, tc.want)
+		}
+	}
+}
+
+func TestScenarioSpinnerAndDice(t *testing.T) {
+	s, err := parseScenario([]byte(`{
+		"turnLimit": 2,
+		"pieces": [
+			{"id": "q", "color": "white", "type": "queen", "square": "a1",
+			 "policy": {"directions": ["up", "right", "up-right"], "weights": [1, 1, 2], "distance": "1d4+1"}}
+		],
+		"winConditions": [
+			{"type": "turnLimit", "winner": "white", "reason": "Queen wanders"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseScenario returned err %v", err)
+	}
+
+	// Without a coin, each direction comes from the dice as the spinner's
+	// section number.
+	events, err := s.run(coinDiceDrawer{&loadedCoin{}, &loadedDice{Outcome: []int{3, 2, 5, 1}}})
+	got := renderText(events)
+	if err != nil {
+		t.Fatalf("run returned err %v", err)
+	}
+	want := []string{
+		"Spun up-right, rolled 3",
+		"White Queen at d4",
+		"Spun right, rolled 5",
+		"White Queen at a4",
+		"Queen wanders, White wins",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("run = %v, wanted %v", got, want)
+	}
+
+	// Real randomness must always produce a finished game.
+	events, err = s.run(rngDrawer{rng: rand.New(rand.NewSource(1))})
+	if ended, ok := lastGameEnded(events); err != nil || !ok || ended.Reason != "Queen wanders" {
+		t.Errorf("run with random values = %v, %v", events, err)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// The original problem always ends for one of these reasons.
+const (
+	rookTakesBishop = "Rook takes bishop"
+	bishopTakesRook = "Bishop can take rook"
+	rookEscapes     = "Rook escapes"
+)
+
+// outcomes lists how the original problem can end, in reporting order.
+var outcomes = []gameEnded{
+	{Winner: internal.BLACK, Reason: rookTakesBishop},
+	{Winner: internal.WHITE, Reason: bishopTakesRook},
+	{Winner: internal.BLACK, Reason: rookEscapes},
+}
+
+// simulationResult aggregates the outcomes of many runs of evaluateProblem.
+type simulationResult struct {
+	// trials is the number of games played.
+	trials int
+
+	// outcomes counts games by the reason they ended.
+	outcomes map[string]int
+
+	// lengths counts games by the number of turns the Rook moved.
+	lengths map[int]int
+}
+
+func newSimulationResult() *simulationResult {
+	return &simulationResult{
+		outcomes: make(map[string]int),
+		lengths:  make(map[int]int),
+	}
+}
+
+// add records the events of one finished game.
+func (r *simulationResult) add(events []event) error {
+	ended, ok := lastGameEnded(events)
+	if !ok {
+		return fmt.Errorf("game did not end: %v", renderText(events))
+	}
+	r.trials++
+	r.outcomes[ended.Reason]++
+	r.lengths[ended.Turn]++
+	return nil
+}
+
+// probability returns the fraction of games ending for reason along with
+// the bounds of its 95% Wilson score confidence interval.
+func (r *simulationResult) probability(reason string) (p, low, high float64) {
+	if r.trials == 0 {
+		return 0, 0, 1
+	}
+	const z = 1.96
+	n := float64(r.trials)
+	p = float64(r.outcomes[reason]) / n
+	center := (p + z*z/(2*n)) / (1 + z*z/n)
+	margin := z / (1 + z*z/n) * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+	return p, center - margin, center + margin
+}
+
+// sortedLengths returns each game length seen, shortest first.
+func (r *simulationResult) sortedLengths() []int {
+	out := make([]int, 0, len(r.lengths))
+	for l := range r.lengths {
+		out = append(out, l)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// report formats the result as human readable lines.
+func (r *simulationResult) report() []string {
+	out := []string{fmt.Sprintf("Trials: %v", r.trials)}
+	for _, o := range outcomes {
+		p, low, high := r.probability(o.Reason)
+		out = append(out, fmt.Sprintf("%v: %.6f (95%% CI %.6f-%.6f)", o, p, low, high))
+	}
+	out = append(out, "Game length in turns:")
+	for _, l := range r.sortedLengths() {
+		out = append(out, fmt.Sprintf("%4d: %.6f", l, float64(r.lengths[l])/float64(r.trials)))
+	}
+	return out
+}
+
+// simulate plays trials games of p with the given coin and dice,
+// aggregating their outcomes.
+func simulate(c coin, d twoDice, p problem, trials int) (*simulationResult, error) {
+	result := newSimulationResult()
+	for i := 0; i < trials; i++ {
+		events, err := p.play(c, d)
+		if err != nil {
+			return result, err
+		}
+		if err := result.add(events); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// runSimulate implements the simulate subcommand.
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	trials := fs.Int("trials", 1000000, "number of games to play")
+	seed := fs.Int64("seed", 0, "seed for the random sources, or 0 to pick one from the clock")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "number of goroutines playing games")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	p := defaultProblem()
+	p.addFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if *trials < 1 {
+		return fmt.Errorf("trials should be at least 1, got %v", *trials)
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	result, err := simulateParallel(*seed, p, *trials, *workers)
+	if err != nil {
+		return err
+	}
+	return writeSimulation(os.Stdout, *format, *seed, result)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateDeterministic(t *testing.T) {
+	// Two games: Tails 5 loses on the first turn, then Heads 2 and Heads 8
+	// escapes after two turns.
+	c := &loadedCoin{Outcome: []bool{false, true, true}}
+	d := &loadedDice{Outcome: []int{5, 2, 8}}
+
+	got, err := simulate(c, d, movesProblem(2), 2)
+	if err != nil {
+		t.Fatalf("simulate returned err %v", err)
+	}
+
+	want := &simulationResult{
+		trials:   2,
+		outcomes: map[string]int{bishopTakesRook: 1, rookEscapes: 1},
+		lengths:  map[int]int{1: 1, 2: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("simulate = %+v, wanted %+v", got, want)
+	}
+}
+
+var probabilityTestCases = []struct {
+	successes, trials int
+	wantP, wantLow    float64
+	wantHigh          float64
+}{
+	{0, 0, 0, 0, 1},
+	{50, 100, 0.5, 0.403830, 0.596170},
+	{0, 100, 0, 0, 0.036995},
+	{100, 100, 1, 0.963005, 1},
+}
+
+func TestSimulationProbability(t *testing.T) {
+	for _, tc := range probabilityTestCases {
+		r := newSimulationResult()
+		r.trials = tc.trials
+		r.outcomes[rookEscapes] = tc.successes
+		p, low, high := r.probability(rookEscapes)
+		if math.Abs(p-tc.wantP) > 1e-6 || math.Abs(low-tc.wantLow) > 1e-6 || math.Abs(high-tc.wantHigh) > 1e-6 {
+			t.Errorf("probability(%v/%v) = %v, %v, %v, wanted %v, %v, %v", tc.successes, tc.trials, p, low, high, tc.wantP, tc.wantLow, tc.wantHigh)
+		}
+	}
+}
+
+func TestSimulateCompletes(t *testing.T) {
+	r, err := simulate(&realCoin{}, &realDice{}, defaultProblem(), 1000)
+	if err != nil {
+		t.Fatalf("simulate with random inputs returned error %v", err)
+	}
+	total := 0
+	for _, o := range outcomes {
+		total += r.outcomes[o.Reason]
+	}
+	if total != 1000 {
+		t.Errorf("simulate outcomes summed to %v, wanted 1000: %v", total, r.outcomes)
+	}
+	if got := len(r.report()); got != 1+len(outcomes)+1+len(r.lengths) {
+		t.Errorf("report() returned %v lines, wanted one per outcome and length plus headers", got)
+	}
+}
+
+func TestRunSimulateRejectsTrials(t *testing.T) {
+	for _, trials := range []string{"0", "-5"} {
+		if err := runSimulate([]string{"-trials", trials}); err == nil {
+			t.Errorf("runSimulate(-trials %v) returned no error", trials)
+		}
+	}
+}
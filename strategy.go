@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+// strategy is the Rook's best play when it chooses between the problem's
+// heads and tails directions instead of tossing a coin.
+type strategy struct {
+	// p is the problem solved.
+	p problem
+
+	// model is p's board and the squares its Bishop attacks.
+	model *problemModel
+
+	// afterRoll is true if the Rook sees the dice before choosing, and false
+	// if it commits to a direction before rolling.
+	afterRoll bool
+
+	// rolls lists each sum of the dice in order.
+	rolls []int
+
+	// choices holds, for each turn starting from the first, the direction the
+	// Rook takes from each square.  Each square maps the roll to the
+	// direction, or roll 0 to the direction for every roll if the Rook
+	// chooses before rolling.
+	choices []map[internal.Position]map[int]string
+
+	// wins holds, for each turn starting from the first, Black's chance of
+	// winning from each square before the roll with best play from then on.
+	wins []map[internal.Position]*big.Rat
+
+	// coin is Black's chance of winning when a coin chooses, as solveExact
+	// finds it.
+	coin *big.Rat
+}
+
+// landing returns Black's chance of winning with best play once the Rook
+// moves from sq in direction d after rolling roll on turn.  The wins of
+// later turns must already be solved.
+func (s *strategy) landing(turn int, sq internal.Position, d string, roll int) (*big.Rat, error) {
+	step := directions[d]
+	dest, ok := s.model.board.Step(sq, step[0]*roll, step[1]*roll)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("rook cannot move off the board from %v", sq)
+	case dest == s.model.bishop:
+		return big.NewRat(1, 1), nil
+	case s.model.attacked[dest]:
+		return new(big.Rat), nil
+	case turn == len(s.wins)-1:
+		// Surviving the last turn is an escape.
+		return big.NewRat(1, 1), nil
+	}
+	return s.wins[turn+1][dest], nil
+}
+
+// solveStrategy finds the Rook's best choice of direction on every turn and
+// square of p by working back from the last turn.  Black wins by escaping or
+// by taking the Bishop, so each choice maximizes the chance of either.  Ties
+// go to the heads direction.
+func solveStrategy(p problem, afterRoll bool) (*strategy, error) {
+	model, err := newProblemModel(p.bishop)
+	if err != nil {
+		return nil, err
+	}
+	dice := twoDiceDistribution()
+	s := &strategy{
+		p:         p,
+		model:     model,
+		afterRoll: afterRoll,
+		choices:   make([]map[internal.Position]map[int]string, p.moves),
+		wins:      make([]map[internal.Position]*big.Rat, p.moves),
+	}
+	for roll := range dice {
+		s.rolls = append(s.rolls, roll)
+	}
+	sort.Ints(s.rolls)
+	for turn := p.moves - 1; turn >= 0; turn-- {
+		s.choices[turn] = make(map[internal.Position]map[int]string)
+		s.wins[turn] = make(map[internal.Position]*big.Rat)
+		for _, sq := range model.board.Squares() {
+			if sq == model.bishop {
+				continue
+			}
+			// weighted holds Black's chance of winning for each direction and
+			// roll, times the chance of the roll.
+			weighted := make(map[string]map[int]*big.Rat)
+			total := make(map[string]*big.Rat)
+			for _, d := range []string{p.heads, p.tails} {
+				weighted[d] = make(map[int]*big.Rat)
+				total[d] = new(big.Rat)
+				for _, roll := range s.rolls {
+					w, err := s.landing(turn, sq, d, roll)
+					if err != nil {
+						return nil, err
+					}
+					weighted[d][roll] = new(big.Rat).Mul(w, dice[roll])
+					total[d].Add(total[d], weighted[d][roll])
+				}
+			}
+			choice := make(map[int]string)
+			win := new(big.Rat)
+			if afterRoll {
+				for _, roll := range s.rolls {
+					choice[roll] = p.heads
+					if weighted[p.tails][roll].Cmp(weighted[p.heads][roll]) > 0 {
+						choice[roll] = p.tails
+					}
+					win.Add(win, weighted[choice[roll]][roll])
+				}
+			} else {
+				choice[0] = p.heads
+				if total[p.tails].Cmp(total[p.heads]) > 0 {
+					choice[0] = p.tails
+				}
+				win = total[choice[0]]
+			}
+			s.choices[turn][sq] = choice
+			s.wins[turn][sq] = win
+		}
+	}
+	coin, err := solveExact(p)
+	if err != nil {
+		return nil, err
+	}
+	s.coin = new(big.Rat).Add(coin.total[rookTakesBishop], coin.total[rookEscapes])
+	return s, nil
+}
+
+// win returns Black's chance of winning from the Rook's starting square with
+// best play.
+func (s *strategy) win() *big.Rat {
+	start, _ := internal.NewPosition(s.p.rook)
+	return s.wins[0][*start]
+}
+
+// when describes when the Rook makes its choice.
+func (s *strategy) when() string {
+	if s.afterRoll {
+		return "after"
+	}
+	return "before"
+}
+
+// cell returns the choice from sq on turn as the first letter of each
+// direction, one per roll in order if the Rook chooses after rolling.
+func (s *strategy) cell(turn int, sq internal.Position) string {
+	choice := s.choices[turn][sq]
+	if !s.afterRoll {
+		return strings.ToUpper(choice[0][:1])
+	}
+	var sb strings.Builder
+	for _, roll := range s.rolls {
+		sb.WriteString(strings.ToUpper(choice[roll][:1]))
+	}
+	return sb.String()
+}
+
+// report formats Black's chance of winning against the coin's, followed by
+// a grid of the Rook's choices on each turn.
+func (s *strategy) report() []string {
+	best, _ := s.win().Float64()
+	coin, _ := s.coin.Float64()
+	out := []string{
+		fmt.Sprintf("Rook chooses %v rolling, Black wins: %v (%.6f)", s.when(), s.win().RatString(), best),
+		fmt.Sprintf("Coin chooses, Black wins: %v (%.6f)", s.coin.RatString(), coin),
+	}
+	width := 1
+	if s.afterRoll {
+		width = len(s.rolls)
+		out = append(out, fmt.Sprintf("Each square lists the direction for rolls %v to %v.", s.rolls[0], s.rolls[len(s.rolls)-1]))
+	}
+	for turn := range s.choices {
+		out = append(out, fmt.Sprintf("Turn %v:", turn+1))
+		out = append(out, squareGrid(s.model.board, width, func(sq internal.Position) string {
+			if _, ok := s.choices[turn][sq]; !ok {
+				return "B"
+			}
+			return s.cell(turn, sq)
+		})...)
+	}
+	return out
+}
+
+// strategyChoice is the Rook's best direction in one situation and Black's
+// chance of winning with it.  Roll is 0 if the Rook chooses before rolling;
+// otherwise BlackWins is the chance given that roll.
+type strategyChoice struct {
+	Turn      int     `json:"turn"`
+	Square    string  `json:"square"`
+	Roll      int     `json:"roll,omitempty"`
+	Direction string  `json:"direction"`
+	BlackWins float64 `json:"blackWins"`
+}
+
+// rows lists every choice by turn, then square in board order, then roll.
+func (s *strategy) rows() ([]strategyChoice, error) {
+	out := make([]strategyChoice, 0)
+	for turn := range s.choices {
+		for _, sq := range s.model.board.Squares() {
+			choice, ok := s.choices[turn][sq]
+			if !ok {
+				continue
+			}
+			if !s.afterRoll {
+				w, _ := s.wins[turn][sq].Float64()
+				out = append(out, strategyChoice{Turn: turn + 1, Square: sq.String(), Direction: choice[0], BlackWins: w})
+				continue
+			}
+			for _, roll := range s.rolls {
+				w, err := s.landing(turn, sq, choice[roll], roll)
+				if err != nil {
+					return nil, err
+				}
+				f, _ := w.Float64()
+				out = append(out, strategyChoice{Turn: turn + 1, Square: sq.String(), Roll: roll, Direction: choice[roll], BlackWins: f})
+			}
+		}
+	}
+	return out, nil
+}
+
+// writeStrategy writes s to w in format.
+func writeStrategy(w io.Writer, format string, s *strategy) error {
+	if err := checkFormat(format); err != nil {
+		return err
+	}
+	if format == "text" {
+		for _, l := range s.report() {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	rows, err := s.rows()
+	if err != nil {
+		return err
+	}
+	best, _ := s.win().Float64()
+	coin, _ := s.coin.Float64()
+	if format == "json" {
+		return writeJSON(w, struct {
+			Choose        string           `json:"choose"`
+			BlackWins     float64          `json:"blackWins"`
+			CoinBlackWins float64          `json:"coinBlackWins"`
+			Policy        []strategyChoice `json:"policy"`
+		}{s.when(), best, coin, rows})
+	}
+	cw := csv.NewWriter(w)
+	out := [][]string{{"turn", "square", "roll", "direction", "black_wins"}}
+	for _, r := range rows {
+		roll := ""
+		if r.Roll != 0 {
+			roll = strconv.Itoa(r.Roll)
+		}
+		out = append(out, []string{strconv.Itoa(r.Turn), r.Square, roll, r.Direction, formatFloat(r.BlackWins)})
+	}
+	return cw.WriteAll(out)
+}
+
+// runStrategy implements the strategy subcommand.
+func runStrategy(args []string) error {
+	fs := flag.NewFlagSet("strategy", flag.ContinueOnError)
+	choose := fs.String("choose", "after", "when the Rook picks its direction: after or before rolling the dice")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	p := defaultProblem()
+	p.addFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if *choose != "after" && *choose != "before" {
+		return fmt.Errorf("choose should be after or before, got %q", *choose)
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	s, err := solveStrategy(p, *choose == "after")
+	if err != nil {
+		return err
+	}
+	return writeStrategy(os.Stdout, *format, s)
+}
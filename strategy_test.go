@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Techbert08/ChessProblem/internal"
+)
+
+func mustStrategy(t *testing.T, p problem, afterRoll bool) *strategy {
+	t.Helper()
+	s, err := solveStrategy(p, afterRoll)
+	if err != nil {
+		t.Fatalf("solveStrategy(%v) returned err %v", afterRoll, err)
+	}
+	return s
+}
+
+func TestSolveStrategyOneMove(t *testing.T) {
+	// From h1, heads loses on rolls of 5 and 7 (h6 and h8) and tails on rolls
+	// of 5 and 9 (e1 and a1), as in TestSolveExactOneMove.
+	testCases := []struct {
+		afterRoll bool
+		want      *big.Rat
+		choices   map[int]string
+	}{
+		// Seeing the roll, only a 5 leaves no safe square.
+		{true, big.NewRat(32, 36), map[int]string{5: "up", 7: "right", 9: "up"}},
+		// Choosing first, right loses 8 ways in 72 rather than 10.
+		{false, big.NewRat(28, 36), map[int]string{0: "right"}},
+	}
+	h1, _ := internal.NewPosition("h1")
+	for _, tc := range testCases {
+		s := mustStrategy(t, movesProblem(1), tc.afterRoll)
+		if s.win().Cmp(tc.want) != 0 {
+			t.Errorf("solveStrategy(%v) Black wins %v, wanted %v", tc.afterRoll, s.win(), tc.want)
+		}
+		if s.coin.Cmp(big.NewRat(3, 4)) != 0 {
+			t.Errorf("solveStrategy(%v) coin Black wins %v, wanted 3/4", tc.afterRoll, s.coin)
+		}
+		for roll, want := range tc.choices {
+			if got := s.choices[0][*h1][roll]; got != want {
+				t.Errorf("solveStrategy(%v) on h1 rolling %v chose %v, wanted %v", tc.afterRoll, roll, got, want)
+			}
+		}
+	}
+}
+
+func TestSolveStrategyBeatsCoin(t *testing.T) {
+	after := mustStrategy(t, movesProblem(15), true)
+	before := mustStrategy(t, movesProblem(15), false)
+	// Seeing the roll can only help, and choosing at all can only beat a coin.
+	if after.win().Cmp(before.win()) <= 0 {
+		t.Errorf("choosing after rolling (%v) should beat choosing before (%v)", after.win().FloatString(6), before.win().FloatString(6))
+	}
+	if before.win().Cmp(before.coin) <= 0 {
+		t.Errorf("choosing before rolling (%v) should beat the coin (%v)", before.win().FloatString(6), before.coin.FloatString(6))
+	}
+	exact, err := solveExact(movesProblem(15))
+	if err != nil {
+		t.Fatalf("solveExact(15) returned err %v", err)
+	}
+	if want := new(big.Rat).Add(exact.total[rookTakesBishop], exact.total[rookEscapes]); after.coin.Cmp(want) != 0 {
+		t.Errorf("coin Black wins %v, wanted %v from solveExact", after.coin, want)
+	}
+}
+
+func TestStrategyReport(t *testing.T) {
+	got := mustStrategy(t, movesProblem(1), false).report()
+	want := []string{
+		"Rook chooses before rolling, Black wins: 7/9 (0.777778)",
+		"Coin chooses, Black wins: 3/4 (0.750000)",
+		"Turn 1:",
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("report() line %v = %q, wanted %q", i, got[i], w)
+		}
+	}
+	// A title, eight ranks and the files for the only turn.
+	if len(got) != len(want)+9 {
+		t.Fatalf("report() wrote %v lines, wanted %v:\n%v", len(got), len(want)+9, strings.Join(got, "\n"))
+	}
+	if !strings.HasPrefix(got[8], "3 ") || !strings.Contains(got[8], " B ") {
+		t.Errorf("report() rank 3 = %q, wanted the Bishop on c3", got[8])
+	}
+	if !strings.HasSuffix(got[10], " R") {
+		t.Errorf("report() rank 1 = %q, wanted right from h1", got[10])
+	}
+
+	after := mustStrategy(t, movesProblem(1), true).report()
+	if want := "Each square lists the direction for rolls 2 to 12."; after[2] != want {
+		t.Errorf("report() line 2 = %q, wanted %q", after[2], want)
+	}
+	// From h1, every roll goes up except 7.
+	if !strings.HasSuffix(after[11], " UUUUURUUUUU") {
+		t.Errorf("report() rank 1 = %q, wanted h1 to go right only on 7", after[11])
+	}
+}
+
+func TestWriteStrategy(t *testing.T) {
+	s := mustStrategy(t, movesProblem(1), true)
+
+	var buf bytes.Buffer
+	if err := writeStrategy(&buf, "csv", s); err != nil {
+		t.Fatalf("writeStrategy(csv) returned err %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("writeStrategy(csv) wrote bad CSV: %v", err)
+	}
+	if len(rows) != 1+63*11 {
+		t.Errorf("writeStrategy(csv) wrote %v rows, wanted %v", len(rows), 1+63*11)
+	}
+	// From a1 a roll of 4 lands on a5 or e1, which the Bishop both attacks.
+	if got, want := strings.Join(rows[3], ","), "1,a1,4,up,0"; got != want {
+		t.Errorf("writeStrategy(csv) row 3 = %q, wanted %q", got, want)
+	}
+
+	buf.Reset()
+	if err := writeStrategy(&buf, "json", s); err != nil {
+		t.Fatalf("writeStrategy(json) returned err %v", err)
+	}
+	var decoded struct {
+		Choose        string
+		BlackWins     float64
+		CoinBlackWins float64
+		Policy        []strategyChoice
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("writeStrategy(json) wrote bad JSON: %v", err)
+	}
+	if decoded.Choose != "after" || decoded.CoinBlackWins != 0.75 || len(decoded.Policy) != 63*11 {
+		t.Errorf("writeStrategy(json) = %v, %v with %v choices", decoded.Choose, decoded.CoinBlackWins, len(decoded.Policy))
+	}
+
+	if err := writeStrategy(&buf, "xml", s); err == nil {
+		t.Errorf("writeStrategy(xml) returned no error")
+	}
+}